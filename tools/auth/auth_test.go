@@ -7,7 +7,7 @@ import (
 )
 
 func TestProvidersCount(t *testing.T) {
-	expected := 30
+	expected := 32
 
 	if total := len(auth.Providers); total != expected {
 		t.Fatalf("Expected %d providers, got %d", expected, total)
@@ -296,4 +296,22 @@ func TestNewProviderByName(t *testing.T) {
 	if _, ok := p.(*auth.ORCID); !ok {
 		t.Error("Expected to be instance of *auth.ORCID")
 	}
+
+	// orcid sandbox
+	p, err = auth.NewProviderByName(auth.NameORCIDSandbox)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.ORCID); !ok {
+		t.Error("Expected to be instance of *auth.ORCID")
+	}
+
+	// orcid member
+	p, err = auth.NewProviderByName(auth.NameORCIDMember)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.ORCID); !ok {
+		t.Error("Expected to be instance of *auth.ORCID")
+	}
 }
@@ -179,7 +179,7 @@ func (p *BaseProvider) sendRawUserInfoRequest(req *http.Request, token *oauth2.T
 	if res.StatusCode >= 400 {
 		return nil, fmt.Errorf(
 			"failed to fetch OAuth2 user profile via %s (%d):\n%s",
-			p.userInfoURL,
+			req.URL.String(),
 			res.StatusCode,
 			string(result),
 		)
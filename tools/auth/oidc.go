@@ -185,6 +185,11 @@ func validateIdTokenSignature(ctx context.Context, idToken string, jwksURL strin
 		return err
 	}
 
+	return verifyIdTokenSignature(idToken, key)
+}
+
+// verifyIdTokenSignature verifies the id_token signature with the provided RSA jwk.
+func verifyIdTokenSignature(idToken string, key *jwk) error {
 	// decode the key params per RFC 7518 (https://tools.ietf.org/html/rfc7518#section-6.3)
 	// and construct a valid publicKey from them
 	// ---
@@ -232,12 +237,17 @@ type jwk struct {
 }
 
 func fetchJWK(ctx context.Context, jwksURL string, kid string) (*jwk, error) {
+	return fetchJWKWithClient(ctx, http.DefaultClient, jwksURL, kid)
+}
+
+// fetchJWKWithClient is similar to fetchJWK but sends the jwks request with the specified client.
+func fetchJWKWithClient(ctx context.Context, client *http.Client, jwksURL string, kid string) (*jwk, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", jwksURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
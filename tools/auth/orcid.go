@@ -3,15 +3,24 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/types"
 	"golang.org/x/oauth2"
 )
 
 func init() {
-	Providers[NameORCID] = wrapFactory(NewORCIDProvider)
+	Providers[NameORCID] = wrapFactory(func() *ORCID { return NewORCIDProvider() })
+	Providers[NameORCIDSandbox] = wrapFactory(NewORCIDSandboxProvider)
+	Providers[NameORCIDMember] = wrapFactory(NewORCIDMemberProvider)
 }
 
 var _ Provider = (*ORCID)(nil)
@@ -19,92 +28,660 @@ var _ Provider = (*ORCID)(nil)
 // NameORCID is the unique name of the ORCID provider.
 const NameORCID string = "ORCID"
 
+// NameORCIDSandbox is the unique name of the ORCID sandbox provider.
+const NameORCIDSandbox string = "ORCIDSandbox"
+
+// NameORCIDMember is the unique name of the ORCID member API provider.
+const NameORCIDMember string = "ORCIDMember"
+
+// ORCIDDefaultAPIBaseURL is the default ORCID public API host.
+const ORCIDDefaultAPIBaseURL string = "https://pub.orcid.org"
+
+// ORCIDDefaultMemberAPIBaseURL is the default ORCID member API host.
+const ORCIDDefaultMemberAPIBaseURL string = "https://api.orcid.org"
+
+const (
+	orcidSandboxSiteURL          = "https://sandbox.orcid.org"
+	orcidSandboxAPIBaseURL       = "https://pub.sandbox.orcid.org"
+	orcidSandboxMemberAPIBaseURL = "https://api.sandbox.orcid.org"
+)
+
+// ORCIDDefaultSiteURL is the default ORCID registry site url.
+const ORCIDDefaultSiteURL string = "https://orcid.org"
+
+// Supported ORCID API versions.
+const (
+	ORCIDAPIVersion21 string = "v2.1"
+	ORCIDAPIVersion30 string = "v3.0"
+)
+
+// ORCIDDefaultAPIVersion is the default ORCID API version.
+const ORCIDDefaultAPIVersion string = ORCIDAPIVersion30
+
+// ORCIDDefaultUserAgent is the default User-Agent header value of the ORCID API requests.
+const ORCIDDefaultUserAgent string = "PocketBase-ORCID (+https://github.com/pocketbase/pocketbase)"
+
+// ORCIDDefaultBatchConcurrency is the default max number of concurrent
+// fetches of a single [ORCID.FetchPublicPersons] or [ORCID.FetchWorkDetails] call.
+const ORCIDDefaultBatchConcurrency int = 4
+
+// ORCIDDefaultMaxBodySize is the default max number of bytes read from a single ORCID API response body.
+const ORCIDDefaultMaxBodySize int64 = 5 << 20
+
+// ORCID OAuth2 scopes.
+//
+// See https://info.orcid.org/ufaqs/what-is-an-oauth-scope-and-which-scopes-does-orcid-support/
+const (
+	ORCIDScopeAuthenticate        string = "/authenticate"
+	ORCIDScopeOpenId              string = "openid"
+	ORCIDScopeReadPublic          string = "/read-public"
+	ORCIDScopeReadLimited         string = "/read-limited"
+	ORCIDScopeActivitiesUpdate    string = "/activities/update"
+	ORCIDScopePersonUpdate        string = "/person/update"
+	ORCIDScopeWebhook             string = "/webhook"
+	ORCIDScopePremiumNotification string = "/premium-notification"
+)
+
+var orcidKnownScopes = []string{
+	ORCIDScopeAuthenticate,
+	ORCIDScopeOpenId,
+	ORCIDScopeReadPublic,
+	ORCIDScopeReadLimited,
+	ORCIDScopeActivitiesUpdate,
+	ORCIDScopePersonUpdate,
+	ORCIDScopeWebhook,
+	ORCIDScopePremiumNotification,
+}
+
+// ErrInvalidORCIDiD is returned when an ORCID iD is not in the
+// "####-####-####-####" format or has invalid check digit.
+var ErrInvalidORCIDiD = errors.New("invalid ORCID iD")
+
+// ErrMissingORCIDiD is returned when the OAuth2 token response
+// doesn't have the "orcid" field (usually because of misconfigured token endpoint).
+var ErrMissingORCIDiD = errors.New("missing orcid token field")
+
+// ErrInsufficientScope is returned when the OAuth2 token doesn't have
+// the scope required for the requested operation (eg. "/read-limited" for the member API).
+var ErrInsufficientScope = errors.New("insufficient ORCID token scope")
+
+// ErrUnsupportedORCIDAPIVersion is returned when the provider APIVersion
+// is not one of the supported ORCID API versions (or when the requested
+// section is not available in the configured version).
+var ErrUnsupportedORCIDAPIVersion = errors.New("unsupported ORCID API version")
+
+// ORCIDEmail defines a single ORCID person email address.
+type ORCIDEmail struct {
+	Email      string `json:"email"`
+	Visibility string `json:"visibility,omitempty"`
+	Primary    bool   `json:"primary"`
+	Verified   bool   `json:"verified"`
+}
+
+// ORCIDResearcherURL defines a single ORCID researcher url (eg. a personal or lab website).
+type ORCIDResearcherURL struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// ORCIDExternalIdentifier defines a single ORCID person external identifier
+// (eg. Scopus Author ID, ResearcherID, ISNI).
+type ORCIDExternalIdentifier struct {
+	Source     *ORCIDSource `json:"source,omitempty"`
+	Type       string       `json:"type"`
+	Value      string       `json:"value"`
+	URL        string       `json:"url"`
+	Visibility string       `json:"visibility,omitempty"`
+}
+
+// ORCID record item visibility values.
+//
+// Limited items are returned only to the member API clients
+// with the "/read-limited" scope and should not be displayed publicly.
+const (
+	ORCIDVisibilityPublic  = "public"
+	ORCIDVisibilityLimited = "limited"
+	ORCIDVisibilityPrivate = "private"
+)
+
+// normalizeORCIDVisibility returns the lowercased visibility value
+// (the ORCID v2 API and some XML responses use uppercase values).
+func normalizeORCIDVisibility(visibility string) string {
+	return strings.ToLower(strings.TrimSpace(visibility))
+}
+
+// ORCIDSource defines the source that asserted an ORCID record item,
+// aka. either a member API client (eg. an institution) or the researcher themself.
+type ORCIDSource struct {
+	// Name is the display name of the source (eg. "Brown University").
+	Name string `json:"name"`
+
+	// ClientId is the ORCID client id of the source (empty if self-asserted).
+	ClientId string `json:"client_id,omitempty"`
+
+	// ORCID is the iD of the source user (set for self-asserted items).
+	ORCID string `json:"orcid,omitempty"`
+}
+
+// IsClient reports whether the item was asserted by an ORCID client
+// (eg. an institution) and not by the researcher themself.
+func (s *ORCIDSource) IsClient() bool {
+	return s != nil && s.ClientId != ""
+}
+
 // ORCID allows authentication via ORCID OAuth2.
 type ORCID struct {
 	BaseProvider
+
+	// APIBaseURL is the ORCID API host used to fetch the user data
+	// (eg. "https://pub.sandbox.orcid.org" or "https://api.orcid.org").
+	//
+	// Fallbacks to [ORCIDDefaultAPIBaseURL] if empty.
+	APIBaseURL string
+
+	// APIVersion is the ORCID API version used to fetch the user data
+	// ([ORCIDAPIVersion30] or [ORCIDAPIVersion21], the "v" prefix is optional).
+	//
+	// The v2.1 responses are parsed with the same parsers. The v2.1 specific
+	// shapes that are handled are the flat /employments and /educations
+	// summaries lists and the /peer-reviews groups without the per review level.
+	// Other v2.1 differences are in fields that are not used (or are optional in v3.0).
+	// The distinctions, invited positions, memberships, qualifications, services
+	// and research resources sections are not available in v2.1 and their fetches
+	// return [ErrUnsupportedORCIDAPIVersion].
+	//
+	// Fallbacks to [ORCIDDefaultAPIVersion] if empty.
+	APIVersion string
+
+	// SiteURL is the ORCID registry site url used to construct the
+	// canonical iD URIs (eg. "https://sandbox.orcid.org").
+	//
+	// Fallbacks to [ORCIDDefaultSiteURL] if empty.
+	SiteURL string
+
+	// SkipEmail indicates that the AuthUser email is not required,
+	// allowing FetchAuthUser to construct the user directly from the
+	// id_token claims (when available) and skip the /person request.
+	SkipEmail bool
+
+	// SkipIdTokenVerification disables the id_token signature and
+	// iss/aud claims verification.
+	//
+	// It is intended to be used only for tests!
+	SkipIdTokenVerification bool
+
+	// StrictEmail instructs FetchAuthUser to return only verified emails
+	// (AuthUser.Email is left empty if the user doesn't have one).
+	//
+	// When disabled, verified emails are still preferred but an unverified
+	// email could be returned as a last resort.
+	StrictEmail bool
+
+	// NameStrategy specifies how AuthUser.Name is assembled from the
+	// ORCID name fields (default to [ORCIDNameCreditThenGiven]).
+	NameStrategy ORCIDNameStrategy
+
+	// SkipNameFallback disables the AuthUser.Name fallback to the ORCID iD
+	// when the user name is private and there is no id_token name.
+	SkipNameFallback bool
+
+	// UseRecord instructs FetchAuthUser to read the person data from the
+	// full /record response instead of the lighter /person endpoint.
+	//
+	// The record response also contains the user preferred locale and
+	// the record creation date which are exported as RawUser "locale"
+	// and "created" keys.
+	UseRecord bool
+
+	// UserAgent specifies the User-Agent header of the ORCID API requests.
+	//
+	// ORCID recommends descriptive User-Agent values so consider including
+	// your app name and contact url (eg. "MyApp/1.0 (+https://example.com)").
+	UserAgent string
+
+	// AcceptLanguage specifies an optional Accept-Language header
+	// of the ORCID API requests (eg. "es", "fr;q=0.9, en;q=0.8").
+	AcceptLanguage string
+
+	// AuthParams specifies additional ORCID authorization url parameters
+	// (eg. "show_login": "true", "lang": "es", "family_names": "Carberry").
+	//
+	// See https://info.orcid.org/ufaqs/how-do-i-customize-the-oauth-sign-in-screen/
+	AuthParams map[string]string
+
+	// Timeout specifies the max duration of a single ORCID API or token
+	// request, including its retries (0 means no timeout).
+	Timeout time.Duration
+
+	// MaxRetries specifies how many times a failed API or token
+	// request will be retried (0 disables the retries).
+	//
+	// Only network errors and 5xx responses are retried.
+	MaxRetries int
+
+	// RetryBaseDelay specifies the initial delay before retrying a failed
+	// request which is doubled (plus some random jitter) on each next attempt.
+	RetryBaseDelay time.Duration
+
+	// MaxRetryAfter specifies the max Retry-After delay that the provider
+	// will wait before retrying a rate limited (429) request.
+	//
+	// Rate limited requests with longer delay fail immediately with [ErrORCIDRateLimited].
+	MaxRetryAfter time.Duration
+
+	// MaxBodySize specifies the max number of bytes read from a single
+	// ORCID API response body (0 means no limit).
+	//
+	// Larger responses fail with [ErrORCIDResponseTooLarge].
+	MaxBodySize int64
+
+	// Gravatar instructs FetchAuthUser to set the AuthUser.AvatarURL to the
+	// Gravatar image url of the user email (ORCID doesn't have profile photos).
+	//
+	// The avatar is set only for verified emails.
+	Gravatar bool
+
+	// GravatarDefault is the Gravatar default image (eg. "identicon", "mp")
+	// used for the users without Gravatar image.
+	//
+	// If set, the users without verified email get the default image
+	// instead of an empty AvatarURL.
+	GravatarDefault string
+
+	// StrictDecoding instructs the person parser to reject the responses
+	// with fields that are not part of the known ORCID v3.0 person schema
+	// with [ErrORCIDSchemaMismatch] (eg. to detect schema drifts in tests).
+	//
+	// The default lenient decoding simply ignores the unknown fields.
+	StrictDecoding bool
+
+	// BatchConcurrency specifies the max number of concurrent fetches
+	// of a single [ORCID.FetchPublicPersons] or [ORCID.FetchWorkDetails] call.
+	//
+	// Fallbacks to [ORCIDDefaultBatchConcurrency] if zero or negative.
+	BatchConcurrency int
+
+	// TokenAuthStyle specifies how the client credentials are sent to the
+	// token endpoint by the code exchange, refresh and client credentials
+	// flows (in the Authorization header or in the POST body).
+	//
+	// Fallbacks to [oauth2.AuthStyleAutoDetect] if zero, which tries
+	// the header first and then the body. Set it explicitly to avoid
+	// the extra request or when the detection results in "invalid_client" errors.
+	TokenAuthStyle oauth2.AuthStyle
+
+	// FollowPrimaryRecord instructs FetchAuthUser to fetch the live
+	// (primary) record when the authenticated iD has been deprecated and
+	// merged into another one (either with 409 Conflict or a redirect response).
+	//
+	// The returned AuthUser.Id is the primary iD and the deprecated
+	// one is exported as RawUser "deprecated_orcid" key.
+	//
+	// If not set, FetchAuthUser fails with [ErrDeprecatedORCID]
+	// (see [ORCIDRecordStatusError.PrimaryId]).
+	FollowPrimaryRecord bool
+
+	// URIAsId instructs FetchAuthUser to set the canonical iD URI
+	// (eg. "https://orcid.org/0000-0002-1825-0097") as AuthUser.Id
+	// instead of the bare iD.
+	URIAsId bool
+
+	// PersonCacheTTL specifies for how long the person responses
+	// are cached and reused by FetchAuthUser (0 disables the cache).
+	//
+	// The default in-memory cache is shared between all ORCID provider instances
+	// with the same configuration and could be cleared with [FlushORCIDPersonCache].
+	PersonCacheTTL time.Duration
+
+	// Cache specifies the cache backend used for the person, conditional
+	// responses and client credentials token caching (eg. a Redis adapter).
+	//
+	// Fallbacks to a shared in-memory cache if nil.
+	Cache ORCIDCache
+
+	// ConditionalRequests enables the conditional ORCID API requests (disabled by default).
+	//
+	// When enabled, the provider stores the responses ETag and Last-Modified headers
+	// together with their body in the provider Cache and sends conditional requests,
+	// reusing the cached body on 304 Not Modified.
+	// The responses are keyed by their url (aka. by iD and record section),
+	// AcceptLanguage and token access class so that the limited visibility
+	// responses are never reused for other tokens.
+	ConditionalRequests bool
+
+	// MemberAPIBaseURL specifies the ORCID member API host that is used
+	// by member providers to refetch the user emails from the /email
+	// endpoint when the regular person response doesn't have a suitable email
+	// (eg. when APIBaseURL is the public API or the emails are with "trusted parties" visibility).
+	//
+	// It is set by default by [WithORCIDMember].
+	MemberAPIBaseURL string
+
+	// Logger specifies an optional logger for the outgoing ORCID API,
+	// token and jwks requests (eg. app.Logger() or slog.Default()).
+	//
+	// The requests are logged with their method, url, status and duration.
+	// The Authorization header and the token url params are always redacted.
+	Logger *slog.Logger
+
+	// Metrics specifies an optional hook that is called after each
+	// outgoing ORCID request with its endpoint, outcome and duration.
+	Metrics ORCIDMetrics
+
+	httpClient *http.Client
+
+	// fetcher is an optional rawFetcher used instead of the default HTTP transport (eg. in tests)
+	fetcher rawFetcher
+
+	member bool
+
+	// optionsErr holds the errors of the invalid options (see [ORCID.OptionsError])
+	optionsErr error
 }
 
-// NewORCIDProvider creates new ORCID provider instance with some defaults.
-func NewORCIDProvider() *ORCID {
-	return &ORCID{BaseProvider{
-		ctx:         context.Background(),
-		displayName: "ORCID",
-		pkce:        true,
-		scopes: []string{
-			"/authenticate",
+// NewORCIDProvider creates new ORCID provider instance with some defaults
+// that could be further customized with the provided options, eg.:
+//
+//	NewORCIDProvider(WithORCIDSandbox(), WithORCIDTimeout(5*time.Second))
+//
+// Because of the default "openid" scope, the token response will also
+// contain an OpenID Connect id_token with the user iD and names.
+func NewORCIDProvider(opts ...ORCIDOption) *ORCID {
+	p := &ORCID{
+		BaseProvider: BaseProvider{
+			ctx:         context.Background(),
+			displayName: "ORCID",
+			pkce:        true,
+			scopes: []string{
+				ORCIDScopeAuthenticate,
+				ORCIDScopeOpenId, // returns an id_token along with the access token
+			},
+			authURL:     "https://orcid.org/oauth/authorize",
+			tokenURL:    "https://orcid.org/oauth/token",
+			userInfoURL: "", // this is set later as it must be derived from the returned token
 		},
-		authURL:     "https://orcid.org/oauth/authorize",
-		tokenURL:    "https://orcid.org/oauth/token",
-		userInfoURL: "", // this is set later as it must be derived from the returned token
-	}}
+		APIBaseURL:     ORCIDDefaultAPIBaseURL,
+		APIVersion:     ORCIDDefaultAPIVersion,
+		SiteURL:        ORCIDDefaultSiteURL,
+		UserAgent:      ORCIDDefaultUserAgent,
+		Timeout:        15 * time.Second,
+		MaxRetries:     2, // aka. 3 attempts in total
+		RetryBaseDelay: 500 * time.Millisecond,
+		MaxRetryAfter:  10 * time.Second,
+		MaxBodySize:    ORCIDDefaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.optionsErr != nil && p.Logger != nil {
+		p.Logger.Warn("Invalid ORCID provider options", slog.Any("error", p.optionsErr))
+	}
+
+	return p
+}
+
+// OptionsError returns the errors of the invalid [NewORCIDProvider] options
+// (eg. unknown [WithORCIDScopes] scopes) or nil if all options were valid.
+func (p *ORCID) OptionsError() error {
+	return p.optionsErr
+}
+
+// NewORCIDProviderWithScopes creates new ORCID provider instance
+// that will request the specified scopes alongside "/authenticate"
+// (eg. [ORCIDScopeReadLimited], [ORCIDScopeActivitiesUpdate], [ORCIDScopeOpenId]).
+//
+// It returns an error if any of the scopes is empty or unknown.
+// If no scopes are specified, the default provider scopes are used.
+func NewORCIDProviderWithScopes(scopes ...string) (*ORCID, error) {
+	p := NewORCIDProvider()
+
+	if len(scopes) == 0 {
+		return p, nil
+	}
+
+	if err := validateORCIDScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	p.setScopes(scopes)
+
+	return p, nil
+}
+
+// setScopes replaces the provider scopes with "/authenticate" and
+// the unique values of the specified scopes.
+func (p *ORCID) setScopes(scopes []string) {
+	p.scopes = []string{ORCIDScopeAuthenticate}
+	for _, scope := range scopes {
+		if !slices.Contains(p.scopes, scope) {
+			p.scopes = append(p.scopes, scope)
+		}
+	}
+}
+
+// NewORCIDSandboxProvider creates new ORCID provider instance that
+// operates against the ORCID sandbox (https://sandbox.orcid.org) environment.
+//
+// It is intended to be used for development and testing.
+func NewORCIDSandboxProvider() *ORCID {
+	return NewORCIDProvider(WithORCIDSandbox())
+}
+
+// NewORCIDMemberProvider creates new ORCID provider instance that
+// operates against the ORCID member API (https://api.orcid.org)
+// and requests the "/read-limited" scope.
+//
+// This allows ORCID member institutions to access the "trusted parties"
+// data of the user record (eg. limited visibility emails and affiliations).
+//
+// Note that the member API accepts only access tokens issued
+// for member API client credentials.
+func NewORCIDMemberProvider() *ORCID {
+	return NewORCIDProvider(WithORCIDMember())
+}
+
+// IsMember reports whether the provider is configured to use the ORCID member API.
+func (p *ORCID) IsMember() bool {
+	return p.member
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+//
+// The provider AuthParams are appended to the generated url
+// unless they are overwritten by the explicitly provided opts.
+func (p *ORCID) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if len(p.AuthParams) == 0 {
+		return p.BaseProvider.BuildAuthURL(state, opts...)
+	}
+
+	allOpts := make([]oauth2.AuthCodeOption, 0, len(p.AuthParams)+len(opts))
+	for key, value := range p.AuthParams {
+		allOpts = append(allOpts, oauth2.SetAuthURLParam(key, value))
+	}
+	allOpts = append(allOpts, opts...)
+
+	return p.BaseProvider.BuildAuthURL(state, allOpts...)
 }
 
 // FetchAuthUser returns an AuthUser instance based on the ORCID's user api.
 //
 // API reference: https://info.orcid.org/documentation/integration-guide/
 func (p *ORCID) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	return p.FetchAuthUserContext(p.ctx, token)
+}
 
-	// deriving userInfoURL from the iD (i.e. username) returned in the token
-	iD, ok := token.Extra("orcid").(string)
-	if !ok || iD == "" {
-		return nil, fmt.Errorf("Failed to get ORCID iD from OAuth2 token")
-	}
-	p.userInfoURL = `https://pub.orcid.org/v3.0/` + iD + `/person`
+// FetchAuthUserContext is similar to [ORCID.FetchAuthUser] but uses the specified ctx for the requests.
+func (p *ORCID) FetchAuthUserContext(ctx context.Context, token *oauth2.Token) (*AuthUser, error) {
 
-	// This is taken from the body of FetchRawUserInfo(),
-	// we need to add "Accept" and "Content-type" header to get JSON, though
-	req, err := http.NewRequestWithContext(p.ctx, "GET", p.userInfoURL, nil)
+	// deriving the person url from the iD (i.e. username) returned in the token
+	//
+	// note: the url is intentionally not stored in p.userInfoURL because
+	// the same provider instance could be used for concurrent requests
+	iD, err := orcidTokenId(token)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-type", "application/json")
-	data, err := p.sendRawUserInfoRequest(req, token)
+
+	// the id_token is returned only when the "openid" scope is requested
+	idTokenClaims, err := p.parseIdToken(ctx, token, iD)
 	if err != nil {
 		return nil, err
 	}
 
-	rawUser := map[string]any{}
-	if err := json.Unmarshal(data, &rawUser); err != nil {
+	idTokenName := orcidIdTokenName(idTokenClaims)
+
+	if p.SkipEmail && idTokenName != "" {
+		rawUser := map[string]any(idTokenClaims)
+		rawUser["orcid_uri"] = p.iDURI(iD)
+		rawUser["raw_schema_version"] = ORCIDRawUserSchemaVersion
+
+		return p.newAuthUser(token, iD, idTokenName, "", rawUser), nil
+	}
+
+	if err := checkORCIDTokenScope(token, p.requiredReadScope()); err != nil {
 		return nil, err
 	}
 
-	extracted := struct {
-		Name struct {
-			GivenNames struct {
-				Value string `json:"value"`
-			} `json:"given-names"`
-			FamilyName struct {
-				Value string `json:"value"`
-			} `json:"family-name"`
-			CreditName struct {
-				Value string `json:"value"`
-			} `json:"credit-name"`
-		} `json:"name"`
-		Emails struct {
-			Email []struct {
-				Email string `json:"email"`
-			} `json:"email"`
-		} `json:"emails"`
-	}{}
-	if err := json.Unmarshal(data, &extracted); err != nil {
+	person, err := p.fetchPerson(ctx, token, iD)
+
+	var statusErr *ORCIDRecordStatusError
+	if p.FollowPrimaryRecord && errors.As(err, &statusErr) && statusErr.PrimaryId != "" {
+		deprecatedId := iD
+		iD = statusErr.PrimaryId
+
+		person, err = p.fetchPerson(ctx, token, iD)
+		if err == nil {
+			person.rawUser["deprecated_orcid"] = deprecatedId
+		}
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
-	name := extracted.Name.CreditName.Value
+	name := person.name
 	if name == "" {
-		// GivenNames is a required field on ORCID, so it will always be set
-		name = extracted.Name.GivenNames.Value
-		if extracted.Name.FamilyName.Value != "" {
-			name += " " + extracted.Name.FamilyName.Value
+		name = idTokenName
+	}
+
+	// the user has hidden their name
+	if name == "" && !p.SkipNameFallback {
+		name = iD
+	}
+
+	return p.newAuthUser(token, iD, name, person.email, person.rawUser), nil
+}
+
+// fetchPerson fetches and parses the person data of the specified iD
+// with the token access class derived from the token scopes.
+//
+// If PersonCacheTTL is set, the parsed result is cached and reused for the TTL duration.
+func (p *ORCID) fetchPerson(ctx context.Context, token *oauth2.Token, iD string) (*orcidParsedPerson, error) {
+	return p.fetchPersonWithClass(ctx, token, iD, orcidTokenClass(token))
+}
+
+// fetchPersonWithClass is similar to fetchPerson but uses the specified
+// token access class (see [orcidTokenClass]).
+//
+// The person cache is separated by the token class because the responses
+// of the same iD could differ (eg. limited visibility emails).
+func (p *ORCID) fetchPersonWithClass(ctx context.Context, token *oauth2.Token, iD string, tokenClass string) (*orcidParsedPerson, error) {
+	cacheKey := p.personCacheKey(iD, tokenClass)
+
+	if p.PersonCacheTTL > 0 {
+		if raw, ok := p.cache().Get(cacheKey); ok {
+			cached := &orcidCachedPerson{}
+			if err := json.Unmarshal(raw, cached); err == nil {
+				return p.parseCachedPerson(cached, iD)
+			}
+		}
+	}
+
+	data := &orcidCachedPerson{}
+	if p.UseRecord {
+		record, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/record"))
+		if err != nil {
+			return nil, err
 		}
+
+		extracted := struct {
+			Person      json.RawMessage        `json:"person"`
+			Preferences orcidRecordPreferences `json:"preferences"`
+			History     orcidRecordHistory     `json:"history"`
+		}{}
+		if err := json.Unmarshal(record, &extracted); err != nil {
+			return nil, err
+		}
+		data.Person = orcidRawOrNull(extracted.Person)
+		data.Locale = extracted.Preferences.Locale
+		data.Created = extracted.History.SubmissionDate.Value
+	} else {
+		raw, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/person"))
+		if err != nil {
+			return nil, err
+		}
+		data.Person = raw
 	}
 
-	email := ""
-	if len(extracted.Emails.Email) > 0 {
-		email = extracted.Emails.Email[0].Email
+	person, err := p.parseCachedPerson(data, iD)
+	if err != nil {
+		return nil, err
 	}
 
+	// limited visibility emails are not included in the public API response
+	if person.email == "" && !p.SkipEmail && p.member && p.MemberAPIBaseURL != "" &&
+		tokenClass == orcidTokenClassReadLimited {
+		data.Emails = p.fetchMemberEmails(ctx, token, iD)
+		if len(data.Emails) > 0 {
+			person.email = selectORCIDEmail(data.Emails, p.StrictEmail)
+			person.rawUser["emails"] = data.Emails
+		}
+	}
+
+	if p.PersonCacheTTL > 0 {
+		if raw, err := json.Marshal(data); err == nil {
+			p.cache().Set(cacheKey, raw, p.PersonCacheTTL)
+		}
+	}
+
+	return person, nil
+}
+
+// parseCachedPerson parses the fetched (or cached) person data.
+//
+// The raw person response is always reparsed so that every call
+// returns a new RawUser map with the concrete value types.
+func (p *ORCID) parseCachedPerson(data *orcidCachedPerson, iD string) (*orcidParsedPerson, error) {
+	rawUser, name, email, err := p.parsePerson(data.Person, iD)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Locale != "" {
+		rawUser["locale"] = data.Locale
+	}
+
+	if err := setORCIDCreated(rawUser, data.Created); err != nil {
+		return nil, err
+	}
+
+	if len(data.Emails) > 0 {
+		email = selectORCIDEmail(data.Emails, p.StrictEmail)
+		rawUser["emails"] = data.Emails
+	}
+
+	return &orcidParsedPerson{
+		rawUser: rawUser,
+		name:    name,
+		email:   email,
+	}, nil
+}
+
+// newAuthUser constructs a new AuthUser from the provided token and extracted user data.
+func (p *ORCID) newAuthUser(token *oauth2.Token, iD, name, email string, rawUser map[string]any) *AuthUser {
 	user := &AuthUser{
 		Name:         name,
 		Username:     iD,
@@ -115,7 +692,472 @@ func (p *ORCID) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 		Id:           iD,
 	}
 
+	if p.URIAsId {
+		user.Id = p.iDURI(iD)
+	}
+
+	if p.Gravatar {
+		verifiedEmail := ""
+		emails, _ := rawUser["emails"].([]ORCIDEmail)
+		for _, e := range emails {
+			if e.Verified && e.Email == email {
+				verifiedEmail = email
+				break
+			}
+		}
+
+		user.AvatarURL = orcidGravatarURL(verifiedEmail, p.GravatarDefault)
+	}
+
 	user.Expiry, _ = types.ParseDateTime(token.Expiry)
 
-	return user, nil
+	return user
+}
+
+// parseIdToken parses the token "id_token" (if any) and returns its claims.
+//
+// It returns nil claims and no error if the token doesn't have an id_token.
+func (p *ORCID) parseIdToken(ctx context.Context, token *oauth2.Token, iD string) (jwt.MapClaims, error) {
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+	t, _, err := jwt.NewParser().ParseUnverified(idToken, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate common claims like exp, iat, etc.
+	err = claims.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	if sub, _ := claims["sub"].(string); sub != iD {
+		return nil, fmt.Errorf("id_token sub must be the authenticated ORCID iD %q, got %q", iD, sub)
+	}
+
+	if p.SkipIdTokenVerification {
+		return claims, nil
+	}
+
+	siteURL := p.siteURL()
+
+	if !claims.VerifyIssuer(siteURL, true) {
+		return nil, fmt.Errorf("iss must be %s, got %#v", siteURL, claims["iss"])
+	}
+
+	if !claims.VerifyAudience(p.clientId, true) {
+		return nil, errors.New("aud must be the developer's client_id")
+	}
+
+	// validate id_token signature
+	// ---
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid header value")
+	}
+
+	jwkCtx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	key, err := fetchORCIDJWK(jwkCtx, p.HTTPClient(), siteURL+"/oauth/jwks", kid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifyIdTokenSignature(idToken, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// orcidJWKsCacheDuration specifies for how long the fetched ORCID jwks are cached.
+const orcidJWKsCacheDuration = 1 * time.Hour
+
+type orcidCachedJWK struct {
+	key     *jwk
+	expires time.Time
+}
+
+// orcidJWKs caches the fetched ORCID jwks by their jwks url and kid.
+var orcidJWKs = store.New[string, *orcidCachedJWK](nil)
+
+// fetchORCIDJWK returns the ORCID RS256 jwk with the specified kid
+// from the cache or from the jwksURL if missing or expired.
+func fetchORCIDJWK(ctx context.Context, client *http.Client, jwksURL string, kid string) (*jwk, error) {
+	cacheKey := jwksURL + "#" + kid
+
+	if cached, ok := orcidJWKs.GetOk(cacheKey); ok && time.Now().Before(cached.expires) {
+		return cached.key, nil
+	}
+
+	key, err := fetchJWKWithClient(ctx, client, jwksURL, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	// ORCID signs the id_token only with RS256
+	if key.Alg == "" {
+		key.Alg = "RS256"
+	}
+	if key.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported jwk alg %q", key.Alg)
+	}
+
+	orcidJWKs.Set(cacheKey, &orcidCachedJWK{
+		key:     key,
+		expires: time.Now().Add(orcidJWKsCacheDuration),
+	})
+
+	return key, nil
+}
+
+// orcidIdTokenName returns the user display name from the provided id_token claims.
+func orcidIdTokenName(claims jwt.MapClaims) string {
+	if name, _ := claims["name"].(string); name != "" {
+		return name
+	}
+
+	givenName, _ := claims["given_name"].(string)
+	familyName, _ := claims["family_name"].(string)
+
+	return strings.TrimSpace(givenName + " " + familyName)
+}
+
+// RefreshToken exchanges the provided ORCID refresh token for a new access token.
+//
+// The returned token also contains the "orcid" and "name" extra token response fields.
+func (p *ORCID) RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("missing ORCID refresh token")
+	}
+
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	var token *oauth2.Token
+
+	err := p.retry(ctx, func() error {
+		var err error
+
+		// the empty access token forces the token source to perform a refresh_token grant request
+		token, err = p.oauth2Config().TokenSource(p.clientCtx(ctx), &oauth2.Token{RefreshToken: refreshToken}).Token()
+
+		return err
+	})
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode != "" {
+			return nil, fmt.Errorf(
+				"failed to refresh ORCID token (%s): %s: %w",
+				retrieveErr.ErrorCode,
+				retrieveErr.ErrorDescription,
+				err,
+			)
+		}
+
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// oauth2Config returns the base provider oauth2 config
+// with the configured token endpoint auth style.
+func (p *ORCID) oauth2Config() *oauth2.Config {
+	config := p.BaseProvider.oauth2Config()
+	config.Endpoint.AuthStyle = p.TokenAuthStyle
+
+	return config
+}
+
+// apiURL returns the ORCID API url of the specified iD record section
+// (eg. "/person") based on the configured API base url and version.
+func (p *ORCID) apiURL(iD string, section string) string {
+	return p.apiURLWithBase(p.APIBaseURL, iD, section)
+}
+
+// memberAPIURL is similar to apiURL but uses the configured MemberAPIBaseURL.
+func (p *ORCID) memberAPIURL(iD string, section string) string {
+	return p.apiURLWithBase(p.MemberAPIBaseURL, iD, section)
+}
+
+func (p *ORCID) apiURLWithBase(baseURL string, iD string, section string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" {
+		baseURL = ORCIDDefaultAPIBaseURL
+	}
+
+	return baseURL + "/" + p.apiVersion() + "/" + iD + section
+}
+
+// apiVersion returns the normalized provider APIVersion (eg. "3.0" -> "v3.0").
+func (p *ORCID) apiVersion() string {
+	version := strings.ToLower(strings.Trim(strings.TrimSpace(p.APIVersion), "/"))
+	if version == "" {
+		return ORCIDDefaultAPIVersion
+	}
+
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	return version
+}
+
+// checkAPIVersion returns [ErrUnsupportedORCIDAPIVersion] if the
+// provider APIVersion is not supported.
+func (p *ORCID) checkAPIVersion() error {
+	switch version := p.apiVersion(); version {
+	case ORCIDAPIVersion30, ORCIDAPIVersion21:
+		return nil
+	default:
+		return fmt.Errorf("%w %q (expected %q or %q)", ErrUnsupportedORCIDAPIVersion, version, ORCIDAPIVersion30, ORCIDAPIVersion21)
+	}
+}
+
+// requireAPIVersion30 returns [ErrUnsupportedORCIDAPIVersion] if the
+// specified v3.0 only section is requested with older API version.
+func (p *ORCID) requireAPIVersion30(section string) error {
+	if version := p.apiVersion(); version == ORCIDAPIVersion21 {
+		return fmt.Errorf("%w: the %s section requires %s, got %s", ErrUnsupportedORCIDAPIVersion, section, ORCIDAPIVersion30, version)
+	}
+
+	return nil
+}
+
+// readTokenId extracts the token ORCID iD and checks whether
+// the token has the scope required to read the record data.
+func (p *ORCID) readTokenId(token *oauth2.Token) (string, error) {
+	iD, err := orcidTokenId(token)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkORCIDTokenScope(token, p.requiredReadScope()); err != nil {
+		return "", err
+	}
+
+	return iD, nil
+}
+
+// requiredReadScope returns the token scope required to read
+// the record data (the member API requires "/read-limited").
+func (p *ORCID) requiredReadScope() string {
+	if p.member {
+		return ORCIDScopeReadLimited
+	}
+
+	return ""
+}
+
+// checkORCIDTokenScope checks whether the token response "scope" field
+// contains the required scope.
+//
+// The check is skipped if the required scope is empty or the token
+// doesn't have a "scope" field (eg. a token restored from a storage).
+func checkORCIDTokenScope(token *oauth2.Token, required string) error {
+	if required == "" {
+		return nil
+	}
+
+	scope, _ := token.Extra("scope").(string)
+	if scope == "" {
+		return nil
+	}
+
+	if slices.Contains(strings.Fields(scope), required) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q is required but the token has only %q", ErrInsufficientScope, required, scope)
+}
+
+// orcidTokenId extracts and validates the ORCID iD returned in the token response.
+func orcidTokenId(token *oauth2.Token) (string, error) {
+	iD, ok := token.Extra("orcid").(string)
+	if !ok || iD == "" {
+		return "", fmt.Errorf("Failed to get ORCID iD from OAuth2 token: %w", ErrMissingORCIDiD)
+	}
+
+	if err := validateORCIDiD(iD); err != nil {
+		return "", err
+	}
+
+	return iD, nil
+}
+
+// ProfileURL validates the specified ORCID iD and returns its canonical
+// public profile url for the provider environment
+// (eg. "https://orcid.org/0000-0002-1825-0097" or
+// "https://sandbox.orcid.org/0000-0002-1825-0097" for the sandbox providers).
+//
+// The iD is normalized with [NormalizeORCIDiD].
+func (p *ORCID) ProfileURL(iD string) (string, error) {
+	iD, err := NormalizeORCIDiD(iD)
+	if err != nil {
+		return "", err
+	}
+
+	return p.iDURI(iD), nil
+}
+
+// iDURI returns the canonical ORCID iD URI for the configured SiteURL.
+func (p *ORCID) iDURI(iD string) string {
+	return p.siteURL() + "/" + iD
+}
+
+// siteURL returns the configured SiteURL without the trailing slash
+// (fallbacks to [ORCIDDefaultSiteURL] if empty).
+func (p *ORCID) siteURL() string {
+	siteURL := strings.TrimRight(p.SiteURL, "/")
+	if siteURL == "" {
+		siteURL = ORCIDDefaultSiteURL
+	}
+
+	return siteURL
+}
+
+// selectORCIDEmail returns the most appropriate address from the provided emails list
+// by preferring the primary verified email, then any verified email and
+// finally (if not in strict mode) the first available email.
+func selectORCIDEmail(emails []ORCIDEmail, strict bool) string {
+	var verified, first string
+
+	for _, email := range emails {
+		if email.Email == "" {
+			continue
+		}
+
+		if email.Verified {
+			if email.Primary {
+				return email.Email
+			}
+			if verified == "" {
+				verified = email.Email
+			}
+		}
+
+		if first == "" {
+			first = email.Email
+		}
+	}
+
+	if verified != "" || strict {
+		return verified
+	}
+
+	return first
+}
+
+// appendUniqueFold appends the trimmed value to the slice only if it is
+// not empty and the slice doesn't already contain it (case-insensitive).
+func appendUniqueFold(slice []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return slice
+	}
+
+	for _, existing := range slice {
+		if strings.EqualFold(existing, value) {
+			return slice
+		}
+	}
+
+	return append(slice, value)
+}
+
+// validateORCIDScopes checks whether all of the provided scopes are known ORCID scopes.
+func validateORCIDScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if scope == "" {
+			return errors.New("ORCID scope cannot be empty")
+		}
+
+		if !slices.Contains(orcidKnownScopes, scope) {
+			return fmt.Errorf("unknown ORCID scope %q, expected one of %v", scope, orcidKnownScopes)
+		}
+	}
+
+	return nil
+}
+
+// NormalizeORCIDiD extracts the bare ORCID iD from the specified user input
+// (eg. "0000-0002-1825-0097", "orcid.org/0000-0002-1825-0097/" or
+// "https://sandbox.orcid.org/0000-0002-1825-0097") and validates it.
+//
+// The surrounding whitespaces, the scheme and host prefix and the trailing
+// slashes are removed. The iD hyphens could be also omitted and the "X"
+// check digit could be lowercase.
+func NormalizeORCIDiD(input string) (string, error) {
+	iD := strings.TrimRight(strings.TrimSpace(input), "/")
+
+	// strip the scheme/host/path prefix (if any)
+	if i := strings.LastIndex(iD, "/"); i >= 0 {
+		iD = iD[i+1:]
+	}
+
+	iD = strings.ToUpper(iD)
+
+	if len(iD) == 16 && !strings.Contains(iD, "-") {
+		iD = iD[0:4] + "-" + iD[4:8] + "-" + iD[8:12] + "-" + iD[12:16]
+	}
+
+	if err := validateORCIDiD(iD); err != nil {
+		return "", err
+	}
+
+	return iD, nil
+}
+
+// validateORCIDiD checks whether the provided id is a valid ORCID iD,
+// aka. 16 characters formatted in 4 hyphen separated groups
+// with a valid ISO 7064 MOD 11-2 check digit (the last character).
+//
+// See https://support.orcid.org/hc/en-us/articles/360006897674-Structure-of-the-ORCID-Identifier
+func validateORCIDiD(id string) error {
+	if len(id) != 19 {
+		return fmt.Errorf("%w %q: expected 19 characters, got %d", ErrInvalidORCIDiD, id, len(id))
+	}
+
+	var total int
+	var digits int
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+
+		if i == 4 || i == 9 || i == 14 {
+			if c != '-' {
+				return fmt.Errorf("%w %q: expected hyphen at position %d", ErrInvalidORCIDiD, id, i+1)
+			}
+			continue
+		}
+
+		digits++
+
+		// check digit
+		if digits == 16 {
+			expected := byte('0' + (12-total%11)%11)
+			if expected == '0'+10 {
+				expected = 'X'
+			}
+
+			if c != expected {
+				return fmt.Errorf("%w %q: invalid check digit", ErrInvalidORCIDiD, id)
+			}
+			break
+		}
+
+		if c < '0' || c > '9' {
+			return fmt.Errorf("%w %q: unexpected character %q", ErrInvalidORCIDiD, id, c)
+		}
+
+		total = (total + int(c-'0')) * 2
+	}
+
+	return nil
 }
@@ -0,0 +1,1159 @@
+package auth
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
+)
+
+// ORCIDDate defines an ORCID "fuzzy" date where the month and day are optional.
+type ORCIDDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month,omitempty"`
+	Day   int `json:"day,omitempty"`
+}
+
+// String returns the date in the "YYYY", "YYYY-MM" or "YYYY-MM-DD" format
+// depending on its precision.
+func (d ORCIDDate) String() string {
+	switch {
+	case d.Month == 0:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day == 0:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	default:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+}
+
+// ORCIDAffiliation defines a single ORCID affiliation summary (eg. employment).
+type ORCIDAffiliation struct {
+	StartDate    *ORCIDDate `json:"start_date,omitempty"`
+	EndDate      *ORCIDDate `json:"end_date,omitempty"`
+	Organization string     `json:"organization"`
+	Department   string     `json:"department,omitempty"`
+	RoleTitle    string     `json:"role_title,omitempty"`
+	PutCode      int64      `json:"put_code"`
+	Visibility   string     `json:"visibility,omitempty"`
+
+	// Source is the client or the researcher that asserted the affiliation (if known).
+	Source *ORCIDSource `json:"source,omitempty"`
+
+	// Current indicates that the affiliation doesn't have an end date.
+	Current bool `json:"current"`
+}
+
+// CurrentORCIDAffiliation returns the first current affiliation
+// (aka. the first one without an end date) or nil if there is none.
+//
+// For the fetched affiliations this is the most recently started current one.
+func CurrentORCIDAffiliation(affiliations []ORCIDAffiliation) *ORCIDAffiliation {
+	for i := range affiliations {
+		if affiliations[i].Current {
+			return &affiliations[i]
+		}
+	}
+
+	return nil
+}
+
+// CurrentORCIDAffiliations returns all current affiliations
+// (eg. concurrent positions at different institutions)
+// preserving their order.
+func CurrentORCIDAffiliations(affiliations []ORCIDAffiliation) []ORCIDAffiliation {
+	result := make([]ORCIDAffiliation, 0, len(affiliations))
+
+	for _, a := range affiliations {
+		if a.Current {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+// compareORCIDDates compares the specified dates with the missing
+// month and day treated as 0 and nil dates ordered before all others.
+func compareORCIDDates(a, b *ORCIDDate) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if c := cmp.Compare(a.Year, b.Year); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(a.Month, b.Month); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Day, b.Day)
+}
+
+// ORCIDWork defines a single ORCID work (aka. publication) summary.
+type ORCIDWork struct {
+	PublicationDate *ORCIDDate                `json:"publication_date,omitempty"`
+	Title           string                    `json:"title"`
+	Type            string                    `json:"type"`
+	Journal         string                    `json:"journal,omitempty"`
+	DOI             string                    `json:"doi,omitempty"`
+	ExternalIds     []ORCIDExternalIdentifier `json:"external_ids"`
+	PutCode         int64                     `json:"put_code"`
+	Visibility      string                    `json:"visibility,omitempty"`
+}
+
+// ORCIDWorkDOIs returns the unique normalized DOIs of the specified works
+// (eg. for publication lists matching), preserving their first occurrence order.
+//
+// The DOIs are extracted from the works "doi" external identifiers.
+// Works without a DOI are skipped.
+func ORCIDWorkDOIs(works []ORCIDWork) []string {
+	dois := []string{}
+	seen := map[string]struct{}{}
+
+	for _, work := range works {
+		for _, id := range work.ExternalIds {
+			if !strings.EqualFold(id.Type, "doi") {
+				continue
+			}
+
+			doi := normalizeORCIDDOI(id.Value)
+			if doi == "" {
+				continue
+			}
+
+			if _, ok := seen[doi]; ok {
+				continue
+			}
+			seen[doi] = struct{}{}
+
+			dois = append(dois, doi)
+		}
+	}
+
+	return dois
+}
+
+// orcidDOIPrefixes lists the common DOI resolver and scheme prefixes (compared lowercased).
+var orcidDOIPrefixes = []string{
+	"https://doi.org/",
+	"http://doi.org/",
+	"https://dx.doi.org/",
+	"http://dx.doi.org/",
+	"doi.org/",
+	"doi:",
+}
+
+// normalizeORCIDDOI normalizes the specified DOI to its bare lowercased
+// "10.prefix/suffix" form (DOIs are case-insensitive).
+//
+// It returns an empty string if the value is not a DOI.
+func normalizeORCIDDOI(value string) string {
+	doi := strings.ToLower(strings.TrimSpace(value))
+
+	for _, prefix := range orcidDOIPrefixes {
+		if strings.HasPrefix(doi, prefix) {
+			doi = strings.TrimSpace(doi[len(prefix):])
+			break
+		}
+	}
+
+	if !strings.HasPrefix(doi, "10.") || !strings.Contains(doi, "/") {
+		return ""
+	}
+
+	return doi
+}
+
+// FetchWorks returns the works summaries of the authenticated ORCID user.
+//
+// Only the first (aka. preferred) work summary of each works group is returned.
+func (p *ORCID) FetchWorks(token *oauth2.Token) ([]ORCIDWork, error) {
+	return p.FetchWorksContext(p.ctx, token)
+}
+
+// FetchWorksContext is similar to [ORCID.FetchWorks] but uses the specified ctx for the requests.
+func (p *ORCID) FetchWorksContext(ctx context.Context, token *oauth2.Token) ([]ORCIDWork, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/works"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDWorks(data)
+}
+
+// PublicWorksCount returns the number of public works (aka. works groups)
+// of the authenticated ORCID user (eg. for "N publications" dashboard counters).
+//
+// The ORCID API doesn't have a dedicated count endpoint and therefore
+// the /works summaries are fetched (and deduplicated/cached similar to [ORCID.FetchWorks])
+// but only the preferred summary visibility of each group is decoded.
+func (p *ORCID) PublicWorksCount(token *oauth2.Token) (int, error) {
+	return p.PublicWorksCountContext(p.ctx, token)
+}
+
+// PublicWorksCountContext is similar to [ORCID.PublicWorksCount] but uses the specified ctx for the requests.
+func (p *ORCID) PublicWorksCountContext(ctx context.Context, token *oauth2.Token) (int, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/works"))
+	if err != nil {
+		return 0, err
+	}
+
+	return countORCIDPublicWorks(data)
+}
+
+// countORCIDPublicWorks counts the raw /works response groups
+// with public preferred summary.
+func countORCIDPublicWorks(data []byte) (int, error) {
+	extracted := struct {
+		Group []struct {
+			WorkSummary []*struct {
+				Visibility string `json:"visibility"`
+			} `json:"work-summary"`
+		} `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	for _, group := range extracted.Group {
+		if len(group.WorkSummary) == 0 || group.WorkSummary[0] == nil {
+			continue
+		}
+
+		if normalizeORCIDVisibility(group.WorkSummary[0].Visibility) == ORCIDVisibilityPublic {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ORCIDWorksPage defines a single page of the works summaries.
+type ORCIDWorksPage struct {
+	Works []ORCIDWork `json:"works"`
+
+	// Total is the number of all works summaries.
+	Total int `json:"total"`
+}
+
+// FetchWorksPage returns up to limit works summaries of the authenticated
+// ORCID user starting from offset (eg. to render large profiles page by page).
+//
+// The ORCID API doesn't support paging of the /works summaries
+// and therefore every call downloads and parses the full /works response
+// and only returns the requested page. Only concurrent calls share a single
+// request, so unless [ORCID.ConditionalRequests] are enabled (to revalidate the
+// response with ETag) paging through N pages downloads the full /works N times.
+// Consider calling [ORCID.FetchWorks] once and paging the returned list instead.
+func (p *ORCID) FetchWorksPage(token *oauth2.Token, offset int, limit int) (*ORCIDWorksPage, error) {
+	return p.FetchWorksPageContext(p.ctx, token, offset, limit)
+}
+
+// FetchWorksPageContext is similar to [ORCID.FetchWorksPage] but uses the specified ctx for the requests.
+func (p *ORCID) FetchWorksPageContext(ctx context.Context, token *oauth2.Token, offset int, limit int) (*ORCIDWorksPage, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, fmt.Errorf("invalid ORCID works page offset %d and limit %d", offset, limit)
+	}
+
+	works, err := p.FetchWorksContext(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ORCIDWorksPage{Total: len(works)}
+
+	start := min(offset, len(works))
+	end := min(start+limit, len(works))
+
+	// clipped so that appending to the page doesn't modify the underlying array
+	page.Works = slices.Clip(works[start:end])
+
+	return page, nil
+}
+
+// FetchWorkDetails fetches the full work records of the specified works
+// (eg. a single [ORCID.FetchWorksPage] page) preserving their order.
+//
+// The details are fetched concurrently with up to [ORCID.BatchConcurrency] workers.
+// The first failed fetch cancels the remaining ones and its error is returned.
+func (p *ORCID) FetchWorkDetails(token *oauth2.Token, works []ORCIDWork) ([]ORCIDWorkDetail, error) {
+	return p.FetchWorkDetailsContext(p.ctx, token, works)
+}
+
+// FetchWorkDetailsContext is similar to [ORCID.FetchWorkDetails] but uses the specified ctx for the requests.
+func (p *ORCID) FetchWorkDetailsContext(ctx context.Context, token *oauth2.Token, works []ORCIDWork) ([]ORCIDWorkDetail, error) {
+	details := make([]ORCIDWorkDetail, len(works))
+
+	concurrency := p.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = ORCIDDefaultBatchConcurrency
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+
+	for i, work := range works {
+		group.Go(func() error {
+			detail, err := p.FetchWorkDetailContext(groupCtx, token, strconv.FormatInt(work.PutCode, 10))
+			if err != nil {
+				return err
+			}
+
+			details[i] = *detail
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// ORCIDContributor defines a single ORCID work contributor (eg. author).
+type ORCIDContributor struct {
+	Name     string `json:"name"`
+	ORCID    string `json:"orcid,omitempty"`
+	Sequence string `json:"sequence,omitempty"` // "first" or "additional"
+	Role     string `json:"role,omitempty"`     // eg. "author", "editor"
+}
+
+// ORCIDWorkDetail defines a full ORCID work record.
+type ORCIDWorkDetail struct {
+	ORCIDWork
+
+	Subtitle         string             `json:"subtitle,omitempty"`
+	ShortDescription string             `json:"short_description,omitempty"` // usually the abstract
+	URL              string             `json:"url,omitempty"`
+	LanguageCode     string             `json:"language_code,omitempty"`
+	CitationType     string             `json:"citation_type,omitempty"` // eg. "bibtex", "formatted-apa"
+	CitationValue    string             `json:"citation_value,omitempty"`
+	Contributors     []ORCIDContributor `json:"contributors"`
+}
+
+// FetchWorkDetail returns the full work record with the specified put-code
+// of the authenticated ORCID user (including the citation and contributors
+// that are not part of the [ORCID.FetchWorks] summaries).
+func (p *ORCID) FetchWorkDetail(token *oauth2.Token, putCode string) (*ORCIDWorkDetail, error) {
+	return p.FetchWorkDetailContext(p.ctx, token, putCode)
+}
+
+// FetchWorkDetailContext is similar to [ORCID.FetchWorkDetail] but uses the specified ctx for the requests.
+func (p *ORCID) FetchWorkDetailContext(ctx context.Context, token *oauth2.Token, putCode string) (*ORCIDWorkDetail, error) {
+	if n, err := strconv.ParseUint(putCode, 10, 64); err != nil || n == 0 {
+		return nil, fmt.Errorf("invalid ORCID work put-code %q: expected a positive integer", putCode)
+	}
+
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/work/"+putCode))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDWorkDetail(data)
+}
+
+// parseORCIDWorkDetail parses an ORCID /work/{put-code} response.
+func parseORCIDWorkDetail(data []byte) (*ORCIDWorkDetail, error) {
+	extracted := struct {
+		orcidWorkSummary
+		ShortDescription string          `json:"short-description"`
+		URL              *orcidJSONValue `json:"url"`
+		LanguageCode     string          `json:"language-code"`
+		Citation         *struct {
+			Type  string `json:"citation-type"`
+			Value string `json:"citation-value"`
+		} `json:"citation"`
+		Contributors struct {
+			Contributor []struct {
+				ORCID *struct {
+					Path string `json:"path"`
+				} `json:"contributor-orcid"`
+				CreditName *orcidJSONValue `json:"credit-name"`
+				Attributes *struct {
+					Sequence string `json:"contributor-sequence"`
+					Role     string `json:"contributor-role"`
+				} `json:"contributor-attributes"`
+			} `json:"contributor"`
+		} `json:"contributors"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	detail := &ORCIDWorkDetail{
+		ORCIDWork:        extracted.toWork(),
+		ShortDescription: strings.TrimSpace(extracted.ShortDescription),
+		LanguageCode:     extracted.LanguageCode,
+		Contributors:     make([]ORCIDContributor, 0, len(extracted.Contributors.Contributor)),
+	}
+
+	if extracted.Title.Subtitle != nil {
+		detail.Subtitle = extracted.Title.Subtitle.Value
+	}
+
+	if extracted.URL != nil {
+		detail.URL = extracted.URL.Value
+	}
+
+	if extracted.Citation != nil {
+		detail.CitationType = extracted.Citation.Type
+		detail.CitationValue = strings.TrimSpace(extracted.Citation.Value)
+	}
+
+	for _, c := range extracted.Contributors.Contributor {
+		contributor := ORCIDContributor{}
+		if c.CreditName != nil {
+			contributor.Name = strings.TrimSpace(c.CreditName.Value)
+		}
+		if c.ORCID != nil {
+			contributor.ORCID = c.ORCID.Path
+		}
+		if c.Attributes != nil {
+			contributor.Sequence = c.Attributes.Sequence
+			contributor.Role = c.Attributes.Role
+		}
+
+		if contributor.Name == "" && contributor.ORCID == "" {
+			continue
+		}
+
+		detail.Contributors = append(detail.Contributors, contributor)
+	}
+
+	return detail, nil
+}
+
+// ORCIDFunding defines a single ORCID funding (eg. grant) summary.
+type ORCIDFunding struct {
+	StartDate    *ORCIDDate `json:"start_date,omitempty"`
+	EndDate      *ORCIDDate `json:"end_date,omitempty"`
+	Title        string     `json:"title"`
+	Type         string     `json:"type"`
+	Organization string     `json:"organization"`
+	Amount       string     `json:"amount,omitempty"`
+	Currency     string     `json:"currency,omitempty"`
+	PutCode      int64      `json:"put_code"`
+}
+
+// FetchFundings returns the fundings summaries of the authenticated ORCID user.
+//
+// Note that the funding amount is usually available only in the full
+// funding record and therefore it will be empty for most summaries.
+func (p *ORCID) FetchFundings(token *oauth2.Token) ([]ORCIDFunding, error) {
+	return p.FetchFundingsContext(p.ctx, token)
+}
+
+// FetchFundingsContext is similar to [ORCID.FetchFundings] but uses the specified ctx for the requests.
+func (p *ORCID) FetchFundingsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDFunding, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/fundings"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDFundings(data)
+}
+
+// ORCIDResearchResource defines a single ORCID research resource summary
+// (eg. an access to a facility, instrument or collection).
+type ORCIDResearchResource struct {
+	StartDate   *ORCIDDate                `json:"start_date,omitempty"`
+	EndDate     *ORCIDDate                `json:"end_date,omitempty"`
+	Title       string                    `json:"title"`
+	Hosts       []string                  `json:"hosts"`
+	ExternalIds []ORCIDExternalIdentifier `json:"external_ids"`
+	PutCode     int64                     `json:"put_code"`
+	Visibility  string                    `json:"visibility,omitempty"`
+
+	// Source is the client that asserted the research resource (if known).
+	Source *ORCIDSource `json:"source,omitempty"`
+}
+
+// FetchResearchResources returns the research resources summaries of the authenticated ORCID user.
+//
+// The Title and the dates of the returned resources are those of the
+// resource proposal and the Hosts are the names of the hosting organizations.
+func (p *ORCID) FetchResearchResources(token *oauth2.Token) ([]ORCIDResearchResource, error) {
+	return p.FetchResearchResourcesContext(p.ctx, token)
+}
+
+// FetchResearchResourcesContext is similar to [ORCID.FetchResearchResources] but uses the specified ctx for the requests.
+func (p *ORCID) FetchResearchResourcesContext(ctx context.Context, token *oauth2.Token) ([]ORCIDResearchResource, error) {
+	if err := p.requireAPIVersion30("/research-resources"); err != nil {
+		return nil, err
+	}
+
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/research-resources"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDResearchResources(data)
+}
+
+// ORCIDPeerReviewGroup defines a single ORCID peer reviews group summary,
+// aka. the reviews of the same review group (eg. a journal).
+type ORCIDPeerReviewGroup struct {
+	// LastCompletionDate is the most recent review completion date (if known).
+	LastCompletionDate *ORCIDDate `json:"last_completion_date,omitempty"`
+
+	// GroupId is the review group identifier (eg. "issn:0264-3561").
+	GroupId string `json:"group_id"`
+
+	// Organization is the name of the review convening organization (eg. the journal publisher).
+	Organization string `json:"organization"`
+
+	// Roles are the unique reviewer roles of the group reviews (eg. "reviewer", "editor").
+	Roles []string `json:"roles"`
+
+	// Count is the number of the group reviews.
+	Count int `json:"count"`
+}
+
+// FetchPeerReviews returns the peer reviews summaries of the authenticated
+// ORCID user grouped by their review group.
+//
+// Only the first (aka. preferred) summary of each review is counted
+// (the v2.1 API doesn't group the summaries of the same review and all are counted).
+func (p *ORCID) FetchPeerReviews(token *oauth2.Token) ([]ORCIDPeerReviewGroup, error) {
+	return p.FetchPeerReviewsContext(p.ctx, token)
+}
+
+// FetchPeerReviewsContext is similar to [ORCID.FetchPeerReviews] but uses the specified ctx for the requests.
+func (p *ORCID) FetchPeerReviewsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDPeerReviewGroup, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/peer-reviews"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDPeerReviews(data)
+}
+
+// FetchEmployments returns the employments of the authenticated ORCID user.
+//
+// All affiliation groups are returned (both current and past, see [ORCIDAffiliation.Current])
+// ordered by their start date descending.
+//
+// API reference: https://info.orcid.org/documentation/api-tutorials/api-tutorial-read-data-on-a-record/
+func (p *ORCID) FetchEmployments(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchEmploymentsContext(p.ctx, token)
+}
+
+// FetchEmploymentsContext is similar to [ORCID.FetchEmployments] but uses the specified ctx for the requests.
+func (p *ORCID) FetchEmploymentsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/employments", "employment-summary")
+}
+
+// FetchEducations returns the education history of the authenticated ORCID user.
+//
+// The RoleTitle of the returned affiliations is usually the obtained degree.
+func (p *ORCID) FetchEducations(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchEducationsContext(p.ctx, token)
+}
+
+// FetchEducationsContext is similar to [ORCID.FetchEducations] but uses the specified ctx for the requests.
+func (p *ORCID) FetchEducationsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/educations", "education-summary")
+}
+
+// FetchDistinctions returns the distinctions (aka. honors and awards)
+// of the authenticated ORCID user.
+//
+// The RoleTitle of the returned affiliations is usually the award name
+// and the StartDate is usually the award date.
+func (p *ORCID) FetchDistinctions(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchDistinctionsContext(p.ctx, token)
+}
+
+// FetchDistinctionsContext is similar to [ORCID.FetchDistinctions] but uses the specified ctx for the requests.
+func (p *ORCID) FetchDistinctionsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/distinctions", "distinction-summary")
+}
+
+// FetchInvitedPositions returns the invited positions (eg. visiting professorships)
+// of the authenticated ORCID user.
+func (p *ORCID) FetchInvitedPositions(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchInvitedPositionsContext(p.ctx, token)
+}
+
+// FetchInvitedPositionsContext is similar to [ORCID.FetchInvitedPositions] but uses the specified ctx for the requests.
+func (p *ORCID) FetchInvitedPositionsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/invited-positions", "invited-position-summary")
+}
+
+// FetchMemberships returns the memberships (eg. professional societies)
+// of the authenticated ORCID user.
+func (p *ORCID) FetchMemberships(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchMembershipsContext(p.ctx, token)
+}
+
+// FetchMembershipsContext is similar to [ORCID.FetchMemberships] but uses the specified ctx for the requests.
+func (p *ORCID) FetchMembershipsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/memberships", "membership-summary")
+}
+
+// FetchQualifications returns the qualifications (eg. professional certifications)
+// of the authenticated ORCID user.
+//
+// The RoleTitle of the returned affiliations is usually the qualification title.
+func (p *ORCID) FetchQualifications(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchQualificationsContext(p.ctx, token)
+}
+
+// FetchQualificationsContext is similar to [ORCID.FetchQualifications] but uses the specified ctx for the requests.
+func (p *ORCID) FetchQualificationsContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/qualifications", "qualification-summary")
+}
+
+// FetchServices returns the services (eg. editorial board or reviewer roles)
+// of the authenticated ORCID user.
+func (p *ORCID) FetchServices(token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.FetchServicesContext(p.ctx, token)
+}
+
+// FetchServicesContext is similar to [ORCID.FetchServices] but uses the specified ctx for the requests.
+func (p *ORCID) FetchServicesContext(ctx context.Context, token *oauth2.Token) ([]ORCIDAffiliation, error) {
+	return p.fetchAffiliations(ctx, token, "/services", "service-summary")
+}
+
+// fetchAffiliations fetches and parses the specified ORCID affiliations section
+// (all affiliation types share the same "affiliation-group" summaries structure).
+func (p *ORCID) fetchAffiliations(ctx context.Context, token *oauth2.Token, section string, summaryKey string) ([]ORCIDAffiliation, error) {
+	if section != "/employments" && section != "/educations" {
+		if err := p.requireAPIVersion30(section); err != nil {
+			return nil, err
+		}
+	}
+
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, section))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseORCIDAffiliations(data, summaryKey)
+}
+
+// parseORCIDAffiliations parses the affiliation groups of an ORCID
+// affiliations section response (eg. /employments).
+//
+// Only the first (aka. preferred) summary of each group is returned
+// because the others are the same affiliation asserted by a different source.
+//
+// The affiliations are ordered by their start date descending
+// (the ones without start date are last).
+func parseORCIDAffiliations(data []byte, summaryKey string) ([]ORCIDAffiliation, error) {
+	extracted := struct {
+		AffiliationGroup []struct {
+			Summaries []map[string]*orcidAffiliationSummary `json:"summaries"`
+		} `json:"affiliation-group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := make([]ORCIDAffiliation, 0, len(extracted.AffiliationGroup))
+
+	for _, group := range extracted.AffiliationGroup {
+		for _, summary := range group.Summaries {
+			s := summary[summaryKey]
+			if s == nil {
+				continue
+			}
+
+			result = append(result, s.toAffiliation())
+			break
+		}
+	}
+
+	// the v2.1 API returns a flat summaries list instead of groups
+	// (eg. {"employment-summary":[...]})
+	if extracted.AffiliationGroup == nil {
+		flat := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return nil, err
+		}
+
+		if raw, ok := flat[summaryKey]; ok {
+			summaries := []*orcidAffiliationSummary{}
+			if err := json.Unmarshal(raw, &summaries); err != nil {
+				return nil, err
+			}
+
+			for _, s := range summaries {
+				if s != nil {
+					result = append(result, s.toAffiliation())
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b ORCIDAffiliation) int {
+		return compareORCIDDates(b.StartDate, a.StartDate)
+	})
+
+	return result, nil
+}
+
+// parseORCIDWorks parses the groups of an ORCID /works response.
+func parseORCIDWorks(data []byte) ([]ORCIDWork, error) {
+	extracted := struct {
+		Group []struct {
+			WorkSummary []*orcidWorkSummary `json:"work-summary"`
+		} `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := make([]ORCIDWork, 0, len(extracted.Group))
+
+	for _, group := range extracted.Group {
+		if len(group.WorkSummary) == 0 || group.WorkSummary[0] == nil {
+			continue
+		}
+
+		result = append(result, group.WorkSummary[0].toWork())
+	}
+
+	return result, nil
+}
+
+// parseORCIDFundings parses the groups of an ORCID /fundings response.
+func parseORCIDFundings(data []byte) ([]ORCIDFunding, error) {
+	extracted := struct {
+		Group []struct {
+			FundingSummary []*orcidFundingSummary `json:"funding-summary"`
+		} `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := make([]ORCIDFunding, 0, len(extracted.Group))
+
+	for _, group := range extracted.Group {
+		if len(group.FundingSummary) == 0 || group.FundingSummary[0] == nil {
+			continue
+		}
+
+		result = append(result, group.FundingSummary[0].toFunding())
+	}
+
+	return result, nil
+}
+
+type orcidFundingSummary struct {
+	PutCode int64 `json:"put-code"`
+	Title   struct {
+		Title *orcidJSONValue `json:"title"`
+	} `json:"title"`
+	Type         string         `json:"type"`
+	StartDate    *orcidJSONDate `json:"start-date"`
+	EndDate      *orcidJSONDate `json:"end-date"`
+	Organization struct {
+		Name string `json:"name"`
+	} `json:"organization"`
+	Amount *struct {
+		Value        string `json:"value"`
+		CurrencyCode string `json:"currency-code"`
+	} `json:"amount"`
+}
+
+func (s *orcidFundingSummary) toFunding() ORCIDFunding {
+	funding := ORCIDFunding{
+		PutCode:      s.PutCode,
+		Type:         s.Type,
+		Organization: s.Organization.Name,
+		StartDate:    s.StartDate.toDate(),
+		EndDate:      s.EndDate.toDate(),
+	}
+
+	if s.Title.Title != nil {
+		funding.Title = s.Title.Title.Value
+	}
+
+	if s.Amount != nil {
+		funding.Amount = s.Amount.Value
+		funding.Currency = s.Amount.CurrencyCode
+	}
+
+	return funding
+}
+
+func parseORCIDResearchResources(data []byte) ([]ORCIDResearchResource, error) {
+	extracted := struct {
+		Group []struct {
+			ResearchResourceSummary []*orcidResearchResourceSummary `json:"research-resource-summary"`
+		} `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := make([]ORCIDResearchResource, 0, len(extracted.Group))
+
+	for _, group := range extracted.Group {
+		if len(group.ResearchResourceSummary) == 0 || group.ResearchResourceSummary[0] == nil {
+			continue
+		}
+
+		result = append(result, group.ResearchResourceSummary[0].toResearchResource())
+	}
+
+	return result, nil
+}
+
+type orcidResearchResourceSummary struct {
+	Source     *orcidJSONSource `json:"source"`
+	PutCode    int64            `json:"put-code"`
+	Visibility string           `json:"visibility"`
+	Proposal   struct {
+		Title struct {
+			Title *orcidJSONValue `json:"title"`
+		} `json:"title"`
+		Hosts struct {
+			Organization []struct {
+				Name string `json:"name"`
+			} `json:"organization"`
+		} `json:"hosts"`
+		ExternalIds orcidJSONExternalIds `json:"external-ids"`
+		StartDate   *orcidJSONDate       `json:"start-date"`
+		EndDate     *orcidJSONDate       `json:"end-date"`
+	} `json:"proposal"`
+}
+
+func (s *orcidResearchResourceSummary) toResearchResource() ORCIDResearchResource {
+	resource := ORCIDResearchResource{
+		PutCode:     s.PutCode,
+		Hosts:       make([]string, 0, len(s.Proposal.Hosts.Organization)),
+		StartDate:   s.Proposal.StartDate.toDate(),
+		EndDate:     s.Proposal.EndDate.toDate(),
+		ExternalIds: s.Proposal.ExternalIds.toExternalIdentifiers(),
+		Source:      s.Source.toSource(),
+		Visibility:  normalizeORCIDVisibility(s.Visibility),
+	}
+
+	if s.Proposal.Title.Title != nil {
+		resource.Title = s.Proposal.Title.Title.Value
+	}
+
+	for _, host := range s.Proposal.Hosts.Organization {
+		if host.Name != "" {
+			resource.Hosts = append(resource.Hosts, host.Name)
+		}
+	}
+
+	return resource
+}
+
+// parseORCIDPeerReviews parses the raw /peer-reviews response.
+//
+// The v3.0 response has 3 levels of nesting - the review groups (eg. a journal),
+// the reviews of each group and the review summaries of each review
+// (one for each source that asserted the review).
+//
+// The v2.1 response doesn't have the middle level and the review summaries
+// are listed directly in the review groups (each summary is counted as a review).
+func parseORCIDPeerReviews(data []byte) ([]ORCIDPeerReviewGroup, error) {
+	extracted := struct {
+		Group []struct {
+			PeerReviewGroup []struct {
+				PeerReviewSummary []*orcidPeerReviewSummary `json:"peer-review-summary"`
+			} `json:"peer-review-group"`
+
+			// v2.1
+			PeerReviewSummary []*orcidPeerReviewSummary `json:"peer-review-summary"`
+		} `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := make([]ORCIDPeerReviewGroup, 0, len(extracted.Group))
+
+	for _, group := range extracted.Group {
+		// the preferred summary of each review
+		reviews := make([]*orcidPeerReviewSummary, 0, len(group.PeerReviewGroup)+len(group.PeerReviewSummary))
+		for _, review := range group.PeerReviewGroup {
+			if len(review.PeerReviewSummary) > 0 {
+				reviews = append(reviews, review.PeerReviewSummary[0])
+			}
+		}
+		reviews = append(reviews, group.PeerReviewSummary...)
+
+		reviewGroup := ORCIDPeerReviewGroup{Roles: []string{}}
+
+		for _, summary := range reviews {
+			if summary == nil {
+				continue
+			}
+
+			reviewGroup.Count++
+
+			if reviewGroup.GroupId == "" {
+				reviewGroup.GroupId = summary.ReviewGroupId
+			}
+
+			if reviewGroup.Organization == "" {
+				reviewGroup.Organization = summary.ConveningOrganization.Name
+			}
+
+			if role := strings.ToLower(summary.ReviewerRole); role != "" && !slices.Contains(reviewGroup.Roles, role) {
+				reviewGroup.Roles = append(reviewGroup.Roles, role)
+			}
+
+			if date := summary.CompletionDate.toDate(); compareORCIDDates(date, reviewGroup.LastCompletionDate) > 0 {
+				reviewGroup.LastCompletionDate = date
+			}
+		}
+
+		if reviewGroup.Count == 0 {
+			continue
+		}
+
+		result = append(result, reviewGroup)
+	}
+
+	return result, nil
+}
+
+type orcidPeerReviewSummary struct {
+	ReviewerRole          string         `json:"reviewer-role"`
+	ReviewGroupId         string         `json:"review-group-id"`
+	CompletionDate        *orcidJSONDate `json:"completion-date"`
+	ConveningOrganization struct {
+		Name string `json:"name"`
+	} `json:"convening-organization"`
+}
+
+type orcidWorkSummary struct {
+	PutCode int64 `json:"put-code"`
+	Title   struct {
+		Title    *orcidJSONValue `json:"title"`
+		Subtitle *orcidJSONValue `json:"subtitle"`
+	} `json:"title"`
+	Type            string               `json:"type"`
+	PublicationDate *orcidJSONDate       `json:"publication-date"`
+	JournalTitle    *orcidJSONValue      `json:"journal-title"`
+	ExternalIds     orcidJSONExternalIds `json:"external-ids"`
+	Visibility      string               `json:"visibility"`
+}
+
+func (s *orcidWorkSummary) toWork() ORCIDWork {
+	work := ORCIDWork{
+		PutCode:         s.PutCode,
+		Type:            s.Type,
+		PublicationDate: s.PublicationDate.toDate(),
+		ExternalIds:     s.ExternalIds.toExternalIdentifiers(),
+		Visibility:      normalizeORCIDVisibility(s.Visibility),
+	}
+
+	if s.Title.Title != nil {
+		work.Title = s.Title.Title.Value
+	}
+
+	if s.JournalTitle != nil {
+		work.Journal = s.JournalTitle.Value
+	}
+
+	for _, id := range work.ExternalIds {
+		if strings.EqualFold(id.Type, "doi") {
+			work.DOI = id.Value
+			break
+		}
+	}
+
+	return work
+}
+
+// orcidJSONExternalIds defines the ORCID API activity "external-ids" JSON representation.
+type orcidJSONExternalIds struct {
+	ExternalId []struct {
+		Type  string          `json:"external-id-type"`
+		Value string          `json:"external-id-value"`
+		URL   *orcidJSONValue `json:"external-id-url"`
+	} `json:"external-id"`
+}
+
+func (ids orcidJSONExternalIds) toExternalIdentifiers() []ORCIDExternalIdentifier {
+	result := make([]ORCIDExternalIdentifier, 0, len(ids.ExternalId))
+
+	for _, id := range ids.ExternalId {
+		if id.Value == "" {
+			continue
+		}
+
+		item := ORCIDExternalIdentifier{
+			Type:  id.Type,
+			Value: id.Value,
+		}
+		if id.URL != nil {
+			item.URL = id.URL.Value
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+type orcidAffiliationSummary struct {
+	Source       *orcidJSONSource `json:"source"`
+	PutCode      int64            `json:"put-code"`
+	Visibility   string           `json:"visibility"`
+	Department   string           `json:"department-name"`
+	RoleTitle    string           `json:"role-title"`
+	StartDate    *orcidJSONDate   `json:"start-date"`
+	EndDate      *orcidJSONDate   `json:"end-date"`
+	Organization struct {
+		Name string `json:"name"`
+	} `json:"organization"`
+}
+
+func (s *orcidAffiliationSummary) toAffiliation() ORCIDAffiliation {
+	affiliation := ORCIDAffiliation{
+		PutCode:      s.PutCode,
+		Organization: s.Organization.Name,
+		Department:   s.Department,
+		RoleTitle:    s.RoleTitle,
+		StartDate:    s.StartDate.toDate(),
+		EndDate:      s.EndDate.toDate(),
+		Source:       s.Source.toSource(),
+		Visibility:   normalizeORCIDVisibility(s.Visibility),
+	}
+
+	affiliation.Current = affiliation.EndDate == nil
+
+	return affiliation
+}
+
+// orcidJSONSource defines the ORCID API item "source" JSON representation.
+type orcidJSONSource struct {
+	SourceORCID *struct {
+		Path string `json:"path"`
+	} `json:"source-orcid"`
+	SourceClientId *struct {
+		Path string `json:"path"`
+	} `json:"source-client-id"`
+	SourceName *orcidJSONValue `json:"source-name"`
+}
+
+// toSource converts the ORCID JSON source into ORCIDSource.
+//
+// It returns nil if the source is missing.
+func (s *orcidJSONSource) toSource() *ORCIDSource {
+	if s == nil {
+		return nil
+	}
+
+	source := &ORCIDSource{}
+	if s.SourceName != nil {
+		source.Name = s.SourceName.Value
+	}
+	if s.SourceClientId != nil {
+		source.ClientId = s.SourceClientId.Path
+	}
+	if s.SourceORCID != nil {
+		source.ORCID = s.SourceORCID.Path
+	}
+
+	if *source == (ORCIDSource{}) {
+		return nil
+	}
+
+	return source
+}
+
+// orcidJSONDate defines the ORCID API fuzzy date JSON representation,
+// eg. {"year":{"value":"2020"},"month":{"value":"01"},"day":null}.
+type orcidJSONDate struct {
+	Year  *orcidJSONValue `json:"year"`
+	Month *orcidJSONValue `json:"month"`
+	Day   *orcidJSONValue `json:"day"`
+}
+
+type orcidJSONValue struct {
+	Value string `json:"value"`
+}
+
+func (v *orcidJSONValue) int() int {
+	if v == nil {
+		return 0
+	}
+
+	n, _ := strconv.Atoi(v.Value)
+
+	return n
+}
+
+// toDate converts the ORCID JSON date into ORCIDDate.
+//
+// It returns nil if the date or its year is missing.
+func (d *orcidJSONDate) toDate() *ORCIDDate {
+	if d == nil {
+		return nil
+	}
+
+	date := &ORCIDDate{
+		Year:  d.Year.int(),
+		Month: d.Month.int(),
+		Day:   d.Day.int(),
+	}
+
+	if date.Year == 0 {
+		return nil
+	}
+
+	return date
+}
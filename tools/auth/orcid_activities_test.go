@@ -0,0 +1,1716 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const testORCIDEmploymentsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"employment-summary": {
+						"created-date": {"value": 1704067200000},
+						"last-modified-date": {"value": 1704067200000},
+						"source": {
+							"source-orcid": null,
+							"source-client-id": {"uri": "https://orcid.org/client/APP-1234567890ABCDEF", "path": "APP-1234567890ABCDEF", "host": "orcid.org"},
+							"source-name": {"value": "Brown University"}
+						},
+						"put-code": 1001,
+						"department-name": "Psychoceramics",
+						"role-title": "Professor",
+						"start-date": {"year": {"value": "1990"}, "month": {"value": "09"}, "day": null},
+						"end-date": null,
+						"organization": {
+							"name": "Brown University",
+							"address": {"city": "Providence", "region": "RI", "country": "US"},
+							"disambiguated-organization": {"disambiguated-organization-identifier": "https://ror.org/05gq02987", "disambiguation-source": "ROR"}
+						},
+						"url": null,
+						"external-ids": null,
+						"display-index": "1",
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/employment/1001"
+					}
+				},
+				{
+					"employment-summary": {
+						"put-code": 1002,
+						"role-title": "Professor (duplicate)",
+						"organization": {"name": "Brown University"},
+						"visibility": "public"
+					}
+				}
+			]
+		},
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"employment-summary": {
+						"created-date": {"value": 1704067200000},
+						"last-modified-date": {"value": 1704067200000},
+						"source": {
+							"source-orcid": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"},
+							"source-client-id": null,
+							"source-name": {"value": "Josiah Carberry"}
+						},
+						"put-code": 1003,
+						"department-name": null,
+						"role-title": "Research Assistant",
+						"start-date": {"year": {"value": "1985"}, "month": null, "day": null},
+						"end-date": {"year": {"value": "1990"}, "month": {"value": "06"}, "day": {"value": "30"}},
+						"organization": {
+							"name": "Wesleyan University",
+							"address": {"city": "Middletown", "region": "CT", "country": "US"},
+							"disambiguated-organization": null
+						},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/employment/1003"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/employments"
+}`
+
+const testORCIDEducationsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"education-summary": {
+						"put-code": 2001,
+						"department-name": "Department of Psychology",
+						"role-title": "PhD",
+						"start-date": {"year": {"value": "1980"}, "month": {"value": "09"}, "day": {"value": "01"}},
+						"end-date": {"year": {"value": "1985"}, "month": {"value": "05"}, "day": null},
+						"organization": {"name": "Brown University", "address": {"city": "Providence", "region": "RI", "country": "US"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/education/2001"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"education-summary": {
+						"put-code": 2002,
+						"department-name": null,
+						"role-title": "BA",
+						"start-date": null,
+						"end-date": {"year": {"value": "1980"}, "month": null, "day": null},
+						"organization": {"name": "Wesleyan University", "address": {"city": "Middletown", "region": "CT", "country": "US"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/education/2002"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/educations"
+}`
+
+const testORCIDDistinctionsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"distinction-summary": {
+						"put-code": 4001,
+						"department-name": "Department of Psychoceramics",
+						"role-title": "Distinguished Teaching Award",
+						"start-date": {"year": {"value": "1999"}, "month": {"value": "05"}, "day": null},
+						"end-date": null,
+						"organization": {"name": "Brown University", "address": {"city": "Providence", "region": "RI", "country": "US"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/distinction/4001"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"distinction-summary": {
+						"source": {
+							"source-orcid": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"},
+							"source-client-id": null,
+							"source-name": {"value": "Josiah Carberry"}
+						},
+						"put-code": 4002,
+						"department-name": null,
+						"role-title": "Fellow",
+						"start-date": {"year": {"value": "2005"}, "month": null, "day": null},
+						"end-date": {"year": {"value": "2006"}, "month": null, "day": null},
+						"organization": {"name": "Society for Psychoceramics", "address": {"city": "London", "region": null, "country": "GB"}},
+						"visibility": "limited",
+						"path": "/0000-0002-1825-0097/distinction/4002"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/distinctions"
+}`
+
+const testORCIDInvitedPositionsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"invited-position-summary": {
+						"put-code": 5001,
+						"department-name": "Institute of Ceramics",
+						"role-title": "Visiting Professor",
+						"start-date": {"year": {"value": "2001"}, "month": {"value": "01"}, "day": null},
+						"end-date": {"year": {"value": "2001"}, "month": {"value": "06"}, "day": {"value": "30"}},
+						"organization": {"name": "University of Oxford", "address": {"city": "Oxford", "region": null, "country": "GB"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/invited-position/5001"
+					}
+				},
+				{
+					"invited-position-summary": {
+						"put-code": 5002,
+						"department-name": null,
+						"role-title": "Visiting Professor (duplicate)",
+						"start-date": {"year": {"value": "2001"}, "month": null, "day": null},
+						"end-date": null,
+						"organization": {"name": "University of Oxford", "address": {"city": "Oxford", "region": null, "country": "GB"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/invited-position/5002"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"invited-position-summary": {
+						"put-code": 5003,
+						"department-name": null,
+						"role-title": "Guest Researcher",
+						"start-date": {"year": {"value": "2010"}, "month": {"value": "03"}, "day": {"value": "15"}},
+						"end-date": null,
+						"organization": {"name": "CERN", "address": {"city": "Geneva", "region": null, "country": "CH"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/invited-position/5003"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/invited-positions"
+}`
+
+const testORCIDMembershipsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"membership-summary": {
+						"put-code": 6001,
+						"department-name": null,
+						"role-title": "Member",
+						"start-date": {"year": {"value": "1988"}, "month": null, "day": null},
+						"end-date": null,
+						"organization": {"name": "American Ceramic Society", "address": {"city": "Westerville", "region": null, "country": "US"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/membership/6001"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"membership-summary": {
+						"put-code": 6002,
+						"department-name": "Standards Committee",
+						"role-title": "Board Member",
+						"start-date": {"year": {"value": "1995"}, "month": {"value": "02"}, "day": null},
+						"end-date": {"year": {"value": "2000"}, "month": {"value": "12"}, "day": null},
+						"organization": {"name": "International Psychoceramics Association", "address": {"city": "Paris", "region": null, "country": "FR"}},
+						"visibility": "limited",
+						"path": "/0000-0002-1825-0097/membership/6002"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/memberships"
+}`
+
+const testORCIDQualificationsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"qualification-summary": {
+						"put-code": 7001,
+						"department-name": null,
+						"role-title": "Certified Kiln Operator",
+						"start-date": {"year": {"value": "1983"}, "month": {"value": "06"}, "day": {"value": "01"}},
+						"end-date": {"year": {"value": "1983"}, "month": {"value": "06"}, "day": {"value": "01"}},
+						"organization": {"name": "Rhode Island School of Design", "address": {"city": "Providence", "region": null, "country": "US"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/qualification/7001"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"qualification-summary": {
+						"put-code": 7002,
+						"department-name": "Professional Registry",
+						"role-title": "Chartered Psychoceramicist",
+						"start-date": {"year": {"value": "1992"}, "month": {"value": "04"}, "day": null},
+						"end-date": null,
+						"organization": {"name": "Institute of Psychoceramics", "address": {"city": "London", "region": null, "country": "GB"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/qualification/7002"
+					}
+				},
+				{
+					"qualification-summary": {
+						"put-code": 7003,
+						"department-name": null,
+						"role-title": "Chartered Psychoceramicist (duplicate)",
+						"start-date": {"year": {"value": "1992"}, "month": null, "day": null},
+						"end-date": null,
+						"organization": {"name": "Institute of Psychoceramics", "address": {"city": "London", "region": null, "country": "GB"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/qualification/7003"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/qualifications"
+}`
+
+const testORCIDServicesJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"affiliation-group": [
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"service-summary": {
+						"put-code": 8001,
+						"department-name": "Editorial Board",
+						"role-title": "Editor-in-Chief",
+						"start-date": {"year": {"value": "2002"}, "month": {"value": "01"}, "day": null},
+						"end-date": null,
+						"organization": {"name": "Journal of Psychoceramics", "address": {"city": "Providence", "region": null, "country": "US"}},
+						"visibility": "limited",
+						"path": "/0000-0002-1825-0097/service/8001"
+					}
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"summaries": [
+				{
+					"service-summary": {
+						"put-code": 8002,
+						"department-name": null,
+						"role-title": "Program Committee Member",
+						"start-date": {"year": {"value": "1997"}, "month": null, "day": null},
+						"end-date": {"year": {"value": "1998"}, "month": null, "day": null},
+						"organization": {"name": "International Conference on Psychoceramics", "address": {"city": "Kyoto", "region": null, "country": "JP"}},
+						"visibility": "public",
+						"path": "/0000-0002-1825-0097/service/8002"
+					}
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/services"
+}`
+
+const testORCIDResearchResourcesJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"group": [
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": []},
+			"research-resource-summary": [
+				{
+					"created-date": {"value": 1704067200000},
+					"last-modified-date": {"value": 1704067200000},
+					"source": {
+						"source-orcid": null,
+						"source-client-id": {"uri": "https://orcid.org/client/APP-1234567890ABCDEF", "path": "APP-1234567890ABCDEF", "host": "orcid.org"},
+						"source-name": {"value": "Brown University"}
+					},
+					"proposal": {
+						"title": {"title": {"value": "Kiln Time for Psychoceramic Samples"}, "translated-title": null},
+						"hosts": {
+							"organization": [
+								{"name": "Brown University Kiln Facility", "address": {"city": "Providence", "region": "RI", "country": "US"}},
+								{"name": "Rhode Island School of Design", "address": {"city": "Providence", "region": "RI", "country": "US"}}
+							]
+						},
+						"external-ids": {
+							"external-id": [
+								{"external-id-type": "proposal-id", "external-id-value": "KILN-2019-42", "external-id-url": null, "external-id-relationship": "self"}
+							]
+						},
+						"start-date": {"year": {"value": "2019"}, "month": {"value": "03"}, "day": null},
+						"end-date": {"year": {"value": "2019"}, "month": {"value": "09"}, "day": null},
+						"url": null
+					},
+					"put-code": 9001,
+					"visibility": "public",
+					"path": "/0000-0002-1825-0097/research-resource/9001"
+				},
+				{
+					"proposal": {"title": {"title": {"value": "Kiln Time (duplicate)"}}},
+					"put-code": 9002,
+					"visibility": "public"
+				}
+			]
+		},
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": []},
+			"research-resource-summary": [
+				{
+					"source": null,
+					"proposal": {
+						"title": {"title": {"value": "Beamtime for Ceramic Cracking Analysis"}},
+						"hosts": {"organization": [{"name": "European Synchrotron Radiation Facility"}]},
+						"external-ids": null,
+						"start-date": {"year": {"value": "2021"}, "month": null, "day": null},
+						"end-date": null
+					},
+					"put-code": 9003,
+					"visibility": "limited"
+				}
+			]
+		},
+		{
+			"research-resource-summary": []
+		}
+	],
+	"path": "/0000-0002-1825-0097/research-resources"
+}`
+
+const testORCIDPeerReviewsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"group": [
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": [{"external-id-type": "peer-review", "external-id-value": "issn:0264-3561"}]},
+			"peer-review-group": [
+				{
+					"external-ids": {"external-id": [{"external-id-type": "source-work-id", "external-id-value": "review-1"}]},
+					"peer-review-summary": [
+						{
+							"source": {"source-name": {"value": "Journal of Psychoceramics"}},
+							"put-code": 10001,
+							"reviewer-role": "reviewer",
+							"review-group-id": "issn:0264-3561",
+							"completion-date": {"year": {"value": "2015"}, "month": {"value": "04"}, "day": null},
+							"convening-organization": {"name": "Psychoceramics Press", "address": {"city": "Providence", "region": "RI", "country": "US"}},
+							"visibility": "public"
+						},
+						{
+							"put-code": 10002,
+							"reviewer-role": "editor",
+							"review-group-id": "issn:0264-3561",
+							"completion-date": {"year": {"value": "2030"}, "month": null, "day": null},
+							"convening-organization": {"name": "Duplicate Source Press"},
+							"visibility": "public"
+						}
+					]
+				},
+				{
+					"external-ids": {"external-id": [{"external-id-type": "source-work-id", "external-id-value": "review-2"}]},
+					"peer-review-summary": [
+						{
+							"put-code": 10003,
+							"reviewer-role": "REVIEWER",
+							"review-group-id": "issn:0264-3561",
+							"completion-date": {"year": {"value": "2018"}, "month": {"value": "11"}, "day": {"value": "02"}},
+							"convening-organization": {"name": "Psychoceramics Press"},
+							"visibility": "public"
+						}
+					]
+				},
+				{
+					"external-ids": {"external-id": [{"external-id-type": "source-work-id", "external-id-value": "review-3"}]},
+					"peer-review-summary": [
+						{
+							"put-code": 10004,
+							"reviewer-role": "editor",
+							"review-group-id": "issn:0264-3561",
+							"completion-date": null,
+							"convening-organization": {"name": "Psychoceramics Press"},
+							"visibility": "public"
+						}
+					]
+				}
+			]
+		},
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": [{"external-id-type": "peer-review", "external-id-value": "orcid-generated:ceramics-conf"}]},
+			"peer-review-group": [
+				{
+					"peer-review-summary": [
+						{
+							"put-code": 10005,
+							"reviewer-role": "chair",
+							"review-group-id": "orcid-generated:ceramics-conf",
+							"completion-date": {"year": {"value": "2012"}, "month": null, "day": null},
+							"convening-organization": {"name": "International Conference on Psychoceramics"},
+							"visibility": "limited"
+						}
+					]
+				}
+			]
+		},
+		{
+			"peer-review-group": [{"peer-review-summary": []}]
+		}
+	],
+	"path": "/0000-0002-1825-0097/peer-reviews"
+}`
+
+const testORCIDWorkDetailJSON = `{
+	"created-date": {"value": 1704067200000},
+	"last-modified-date": {"value": 1704067200000},
+	"source": {"source-name": {"value": "Crossref"}},
+	"put-code": 3001,
+	"path": "/0000-0002-1825-0097/work/3001",
+	"title": {
+		"title": {"value": "Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory"},
+		"subtitle": {"value": "A Psychoceramics Approach"},
+		"translated-title": null
+	},
+	"journal-title": {"value": "Journal of Psychoceramics"},
+	"short-description": " The silly string theory is introduced. ",
+	"citation": {
+		"citation-type": "bibtex",
+		"citation-value": "@article{carberry2008,title={Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory},author={Carberry, Josiah},journal={Journal of Psychoceramics},year={2008}}"
+	},
+	"type": "journal-article",
+	"publication-date": {"year": {"value": "2008"}, "month": {"value": "08"}, "day": {"value": "13"}},
+	"external-ids": {
+		"external-id": [
+			{
+				"external-id-type": "doi",
+				"external-id-value": "10.5555/12345678",
+				"external-id-url": {"value": "https://doi.org/10.5555/12345678"},
+				"external-id-relationship": "self"
+			}
+		]
+	},
+	"url": {"value": "https://doi.org/10.5555/12345678"},
+	"contributors": {
+		"contributor": [
+			{
+				"contributor-orcid": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"},
+				"credit-name": {"value": "Josiah Carberry"},
+				"contributor-email": null,
+				"contributor-attributes": {"contributor-sequence": "first", "contributor-role": "author"}
+			},
+			{
+				"contributor-orcid": null,
+				"credit-name": {"value": "Truman Grayson"},
+				"contributor-email": null,
+				"contributor-attributes": {"contributor-sequence": "additional", "contributor-role": "author"}
+			},
+			{
+				"contributor-orcid": null,
+				"credit-name": null,
+				"contributor-attributes": null
+			}
+		]
+	},
+	"language-code": "en",
+	"country": null,
+	"visibility": "public"
+}`
+
+const testORCIDWorksJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"group": [
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {
+				"external-id": [
+					{
+						"external-id-type": "doi",
+						"external-id-value": "10.5555/12345678",
+						"external-id-normalized": {"value": "10.5555/12345678", "transient": true},
+						"external-id-url": {"value": "https://doi.org/10.5555/12345678"},
+						"external-id-relationship": "self"
+					}
+				]
+			},
+			"work-summary": [
+				{
+					"put-code": 3001,
+					"created-date": {"value": 1704067200000},
+					"last-modified-date": {"value": 1704067200000},
+					"source": {"source-name": {"value": "Crossref"}},
+					"title": {"title": {"value": "Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory"}, "subtitle": null, "translated-title": null},
+					"external-ids": {
+						"external-id": [
+							{
+								"external-id-type": "doi",
+								"external-id-value": "10.5555/12345678",
+								"external-id-normalized": {"value": "10.5555/12345678", "transient": true},
+								"external-id-url": {"value": "https://doi.org/10.5555/12345678"},
+								"external-id-relationship": "self"
+							},
+							{
+								"external-id-type": "issn",
+								"external-id-value": "0264-3561",
+								"external-id-url": null,
+								"external-id-relationship": "part-of"
+							}
+						]
+					},
+					"url": {"value": "https://doi.org/10.5555/12345678"},
+					"type": "journal-article",
+					"publication-date": {"year": {"value": "2008"}, "month": {"value": "08"}, "day": {"value": "13"}},
+					"journal-title": {"value": "Journal of Psychoceramics"},
+					"visibility": "public",
+					"path": "/0000-0002-1825-0097/work/3001",
+					"display-index": "1"
+				},
+				{
+					"put-code": 3002,
+					"title": {"title": {"value": "Duplicated work from another source"}},
+					"type": "journal-article",
+					"visibility": "public"
+				}
+			]
+		},
+		{
+			"last-modified-date": {"value": 1704067200000},
+			"external-ids": {"external-id": []},
+			"work-summary": [
+				{
+					"put-code": 3003,
+					"title": {"title": {"value": "The Psychoceramics Handbook"}, "subtitle": null, "translated-title": null},
+					"external-ids": {"external-id": []},
+					"url": null,
+					"type": "book-chapter",
+					"publication-date": {"year": {"value": "1995"}, "month": null, "day": null},
+					"journal-title": null,
+					"visibility": "public",
+					"path": "/0000-0002-1825-0097/work/3003",
+					"display-index": "0"
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/works"
+}`
+
+const testORCIDFundingsJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"group": [
+		{
+			"external-ids": {"external-id": [{"external-id-type": "grant_number", "external-id-value": "PSY-1234", "external-id-relationship": "self"}]},
+			"funding-summary": [
+				{
+					"put-code": 4001,
+					"title": {"title": {"value": "Psychoceramics of Cracked Pots"}, "translated-title": null},
+					"type": "grant",
+					"start-date": {"year": {"value": "2010"}, "month": {"value": "01"}, "day": null},
+					"end-date": {"year": {"value": "2013"}, "month": {"value": "12"}, "day": null},
+					"organization": {"name": "National Science Foundation", "address": {"city": "Alexandria", "region": "VA", "country": "US"}},
+					"amount": {"value": "150000", "currency-code": "USD"},
+					"visibility": "public",
+					"path": "/0000-0002-1825-0097/funding/4001"
+				}
+			]
+		},
+		{
+			"external-ids": {"external-id": []},
+			"funding-summary": [
+				{
+					"put-code": 4002,
+					"title": {"title": {"value": "Travel award"}},
+					"type": "award",
+					"start-date": {"year": {"value": "2015"}},
+					"end-date": null,
+					"organization": {"name": "Brown University"},
+					"visibility": "public",
+					"path": "/0000-0002-1825-0097/funding/4002"
+				}
+			]
+		}
+	],
+	"path": "/0000-0002-1825-0097/fundings"
+}`
+
+func TestORCIDDateString(t *testing.T) {
+	scenarios := []struct {
+		date     ORCIDDate
+		expected string
+	}{
+		{ORCIDDate{}, "0000"},
+		{ORCIDDate{Year: 2020}, "2020"},
+		{ORCIDDate{Year: 2020, Month: 1}, "2020-01"},
+		{ORCIDDate{Year: 2020, Month: 1, Day: 2}, "2020-01-02"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.expected, func(t *testing.T) {
+			if v := s.date.String(); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchEmployments(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/employments", testORCIDEmploymentsJSON)
+	defer cleanup()
+
+	employments, err := p.FetchEmployments(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	expected := []ORCIDAffiliation{
+		{
+			PutCode:      1001,
+			Organization: "Brown University",
+			Department:   "Psychoceramics",
+			RoleTitle:    "Professor",
+			StartDate:    &ORCIDDate{Year: 1990, Month: 9},
+			Visibility:   ORCIDVisibilityPublic,
+			Source:       &ORCIDSource{Name: "Brown University", ClientId: "APP-1234567890ABCDEF"},
+			Current:      true,
+		},
+		{
+			PutCode:      1003,
+			Organization: "Wesleyan University",
+			RoleTitle:    "Research Assistant",
+			StartDate:    &ORCIDDate{Year: 1985},
+			EndDate:      &ORCIDDate{Year: 1990, Month: 6, Day: 30},
+			Visibility:   ORCIDVisibilityPublic,
+			Source:       &ORCIDSource{Name: "Josiah Carberry", ORCID: "0000-0002-1825-0097"},
+			Current:      false,
+		},
+	}
+
+	if !reflect.DeepEqual(employments, expected) {
+		t.Fatalf("Expected employments\n%#v\ngot\n%#v", expected, employments)
+	}
+
+	if !employments[0].Source.IsClient() || employments[1].Source.IsClient() {
+		t.Fatal("Expected only the first employment to be client asserted")
+	}
+
+	current := CurrentORCIDAffiliation(employments)
+	if current == nil || current.PutCode != 1001 {
+		t.Fatalf("Expected current affiliation with put-code 1001, got %#v", current)
+	}
+}
+
+func TestORCIDFetchEmploymentsMultipleCurrent(t *testing.T) {
+	// the groups are intentionally not in start date order
+	body := `{"affiliation-group": [
+		{"summaries": [{"employment-summary": {
+			"put-code": 1,
+			"organization": {"name": "Past University"},
+			"start-date": {"year": {"value": "2010"}},
+			"end-date": {"year": {"value": "2015"}}
+		}}]},
+		{"summaries": [{"employment-summary": {
+			"put-code": 2,
+			"organization": {"name": "First University"},
+			"start-date": {"year": {"value": "2016"}, "month": {"value": "02"}},
+			"end-date": null
+		}}]},
+		{"summaries": [{"employment-summary": {
+			"put-code": 3,
+			"organization": {"name": "Second University"},
+			"start-date": {"year": {"value": "2016"}, "month": {"value": "09"}},
+			"end-date": null
+		}}]},
+		{"summaries": [{"employment-summary": {
+			"put-code": 4,
+			"organization": {"name": "Undated Institute"},
+			"start-date": null,
+			"end-date": null
+		}}]}
+	]}`
+
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/employments", body)
+	defer cleanup()
+
+	employments, err := p.FetchEmployments(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	putCodes := make([]int64, len(employments))
+	for i, e := range employments {
+		putCodes[i] = e.PutCode
+	}
+
+	if expected := []int64{3, 2, 1, 4}; !slices.Equal(putCodes, expected) {
+		t.Fatalf("Expected put codes %v, got %v", expected, putCodes)
+	}
+
+	if employments[2].Current {
+		t.Fatal("Expected the past employment to not be current")
+	}
+
+	current := CurrentORCIDAffiliations(employments)
+	if len(current) != 3 || current[0].PutCode != 3 || current[1].PutCode != 2 || current[2].PutCode != 4 {
+		t.Fatalf("Expected 3 current employments, got %#v", current)
+	}
+
+	if c := CurrentORCIDAffiliation(employments); c == nil || c.PutCode != 3 {
+		t.Fatalf("Expected the most recent current employment, got %#v", c)
+	}
+}
+
+func TestCompareORCIDDates(t *testing.T) {
+	scenarios := []struct {
+		a, b     *ORCIDDate
+		expected int
+	}{
+		{nil, nil, 0},
+		{nil, &ORCIDDate{Year: 2000}, -1},
+		{&ORCIDDate{Year: 2000}, nil, 1},
+		{&ORCIDDate{Year: 2000}, &ORCIDDate{Year: 2000}, 0},
+		{&ORCIDDate{Year: 2000}, &ORCIDDate{Year: 2000, Month: 1}, -1},
+		{&ORCIDDate{Year: 2001}, &ORCIDDate{Year: 2000, Month: 12, Day: 31}, 1},
+		{&ORCIDDate{Year: 2000, Month: 5, Day: 2}, &ORCIDDate{Year: 2000, Month: 5, Day: 1}, 1},
+	}
+
+	for i, s := range scenarios {
+		if v := compareORCIDDates(s.a, s.b); v != s.expected {
+			t.Fatalf("[%d] Expected %d, got %d", i, s.expected, v)
+		}
+	}
+}
+
+func TestORCIDFetchEmploymentsEmpty(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/employments", `{"affiliation-group":[],"path":"/0000-0002-1825-0097/employments"}`)
+	defer cleanup()
+
+	employments, err := p.FetchEmployments(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if employments == nil || len(employments) != 0 {
+		t.Fatalf("Expected empty non-nil employments, got %#v", employments)
+	}
+
+	if current := CurrentORCIDAffiliation(employments); current != nil {
+		t.Fatalf("Expected nil current affiliation, got %#v", current)
+	}
+}
+
+func TestORCIDFetchEmploymentsAPIVersion21(t *testing.T) {
+	body := `{
+		"last-modified-date": {"value": 1704067200000},
+		"employment-summary": [
+			{
+				"put-code": 1001,
+				"department-name": "Psychoceramics",
+				"role-title": "Professor",
+				"start-date": {"year": {"value": "1990"}, "month": {"value": "09"}, "day": null},
+				"end-date": null,
+				"organization": {"name": "Brown University"},
+				"visibility": "PUBLIC",
+				"path": "/0000-0002-1825-0097/employment/1001"
+			},
+			{
+				"put-code": 1003,
+				"role-title": "Research Assistant",
+				"start-date": {"year": {"value": "1985"}, "month": null, "day": null},
+				"end-date": {"year": {"value": "1990"}, "month": null, "day": null},
+				"organization": {"name": "Wesleyan University"},
+				"visibility": "PUBLIC"
+			}
+		],
+		"path": "/0000-0002-1825-0097/employments"
+	}`
+
+	p, token, cleanup := testORCIDSectionProvider(t, "/v2.1/0000-0002-1825-0097/employments", body)
+	defer cleanup()
+
+	p.APIVersion = ORCIDAPIVersion21
+
+	employments, err := p.FetchEmployments(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	expected := []ORCIDAffiliation{
+		{
+			PutCode:      1001,
+			Organization: "Brown University",
+			Department:   "Psychoceramics",
+			RoleTitle:    "Professor",
+			StartDate:    &ORCIDDate{Year: 1990, Month: 9},
+			Visibility:   ORCIDVisibilityPublic,
+			Current:      true,
+		},
+		{
+			PutCode:      1003,
+			Organization: "Wesleyan University",
+			RoleTitle:    "Research Assistant",
+			StartDate:    &ORCIDDate{Year: 1985},
+			EndDate:      &ORCIDDate{Year: 1990},
+			Visibility:   ORCIDVisibilityPublic,
+		},
+	}
+
+	if !reflect.DeepEqual(employments, expected) {
+		t.Fatalf("Expected employments\n%#v\ngot\n%#v", expected, employments)
+	}
+
+	// v3.0 only sections
+	if _, err := p.FetchDistinctions(token); !errors.Is(err, ErrUnsupportedORCIDAPIVersion) {
+		t.Fatalf("Expected ErrUnsupportedORCIDAPIVersion for distinctions, got %v", err)
+	}
+
+	if _, err := p.FetchResearchResources(token); !errors.Is(err, ErrUnsupportedORCIDAPIVersion) {
+		t.Fatalf("Expected ErrUnsupportedORCIDAPIVersion for research resources, got %v", err)
+	}
+
+	// unsupported version
+	p.APIVersion = "v4.0"
+	if _, err := p.FetchEmployments(token); !errors.Is(err, ErrUnsupportedORCIDAPIVersion) {
+		t.Fatalf("Expected ErrUnsupportedORCIDAPIVersion for v4.0, got %v", err)
+	}
+}
+
+func TestORCIDFetchEducations(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		body     string
+		expected []ORCIDAffiliation
+	}{
+		{
+			"multiple educations",
+			testORCIDEducationsJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      2001,
+					Organization: "Brown University",
+					Department:   "Department of Psychology",
+					RoleTitle:    "PhD",
+					StartDate:    &ORCIDDate{Year: 1980, Month: 9, Day: 1},
+					EndDate:      &ORCIDDate{Year: 1985, Month: 5},
+					Visibility:   ORCIDVisibilityPublic,
+				},
+				{
+					PutCode:      2002,
+					Organization: "Wesleyan University",
+					RoleTitle:    "BA",
+					EndDate:      &ORCIDDate{Year: 1980},
+					Visibility:   ORCIDVisibilityPublic,
+				},
+			},
+		},
+		{
+			"empty section",
+			`{"affiliation-group":[]}`,
+			[]ORCIDAffiliation{},
+		},
+		{
+			"null section",
+			`{"affiliation-group":null}`,
+			[]ORCIDAffiliation{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/educations", s.body)
+			defer cleanup()
+
+			educations, err := p.FetchEducations(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(educations, s.expected) {
+				t.Fatalf("Expected educations\n%#v\ngot\n%#v", s.expected, educations)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAffiliationSections(t *testing.T) {
+	// the sections that share the employments/educations affiliation groups structure
+	scenarios := []struct {
+		section  string
+		fetch    func(p *ORCID, token *oauth2.Token) ([]ORCIDAffiliation, error)
+		body     string
+		expected []ORCIDAffiliation
+	}{
+		{
+			"distinctions",
+			(*ORCID).FetchDistinctions,
+			testORCIDDistinctionsJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      4002,
+					Organization: "Society for Psychoceramics",
+					RoleTitle:    "Fellow",
+					StartDate:    &ORCIDDate{Year: 2005},
+					EndDate:      &ORCIDDate{Year: 2006},
+					Visibility:   ORCIDVisibilityLimited,
+					Source:       &ORCIDSource{Name: "Josiah Carberry", ORCID: "0000-0002-1825-0097"},
+				},
+				{
+					PutCode:      4001,
+					Organization: "Brown University",
+					Department:   "Department of Psychoceramics",
+					RoleTitle:    "Distinguished Teaching Award",
+					StartDate:    &ORCIDDate{Year: 1999, Month: 5},
+					Visibility:   ORCIDVisibilityPublic,
+					Current:      true,
+				},
+			},
+		},
+		{
+			"invited-positions",
+			(*ORCID).FetchInvitedPositions,
+			testORCIDInvitedPositionsJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      5003,
+					Organization: "CERN",
+					RoleTitle:    "Guest Researcher",
+					StartDate:    &ORCIDDate{Year: 2010, Month: 3, Day: 15},
+					Visibility:   ORCIDVisibilityPublic,
+					Current:      true,
+				},
+				{
+					PutCode:      5001,
+					Organization: "University of Oxford",
+					Department:   "Institute of Ceramics",
+					RoleTitle:    "Visiting Professor",
+					StartDate:    &ORCIDDate{Year: 2001, Month: 1},
+					EndDate:      &ORCIDDate{Year: 2001, Month: 6, Day: 30},
+					Visibility:   ORCIDVisibilityPublic,
+				},
+			},
+		},
+		{
+			"memberships",
+			(*ORCID).FetchMemberships,
+			testORCIDMembershipsJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      6002,
+					Organization: "International Psychoceramics Association",
+					Department:   "Standards Committee",
+					RoleTitle:    "Board Member",
+					StartDate:    &ORCIDDate{Year: 1995, Month: 2},
+					EndDate:      &ORCIDDate{Year: 2000, Month: 12},
+					Visibility:   ORCIDVisibilityLimited,
+				},
+				{
+					PutCode:      6001,
+					Organization: "American Ceramic Society",
+					RoleTitle:    "Member",
+					StartDate:    &ORCIDDate{Year: 1988},
+					Visibility:   ORCIDVisibilityPublic,
+					Current:      true,
+				},
+			},
+		},
+		{
+			"qualifications",
+			(*ORCID).FetchQualifications,
+			testORCIDQualificationsJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      7002,
+					Organization: "Institute of Psychoceramics",
+					Department:   "Professional Registry",
+					RoleTitle:    "Chartered Psychoceramicist",
+					StartDate:    &ORCIDDate{Year: 1992, Month: 4},
+					Visibility:   ORCIDVisibilityPublic,
+					Current:      true,
+				},
+				{
+					PutCode:      7001,
+					Organization: "Rhode Island School of Design",
+					RoleTitle:    "Certified Kiln Operator",
+					StartDate:    &ORCIDDate{Year: 1983, Month: 6, Day: 1},
+					EndDate:      &ORCIDDate{Year: 1983, Month: 6, Day: 1},
+					Visibility:   ORCIDVisibilityPublic,
+				},
+			},
+		},
+		{
+			"services",
+			(*ORCID).FetchServices,
+			testORCIDServicesJSON,
+			[]ORCIDAffiliation{
+				{
+					PutCode:      8001,
+					Organization: "Journal of Psychoceramics",
+					Department:   "Editorial Board",
+					RoleTitle:    "Editor-in-Chief",
+					StartDate:    &ORCIDDate{Year: 2002, Month: 1},
+					Visibility:   ORCIDVisibilityLimited,
+					Current:      true,
+				},
+				{
+					PutCode:      8002,
+					Organization: "International Conference on Psychoceramics",
+					RoleTitle:    "Program Committee Member",
+					StartDate:    &ORCIDDate{Year: 1997},
+					EndDate:      &ORCIDDate{Year: 1998},
+					Visibility:   ORCIDVisibilityPublic,
+				},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.section, func(t *testing.T) {
+			bodies := []struct {
+				name     string
+				body     string
+				expected []ORCIDAffiliation
+			}{
+				{"fixture", s.body, s.expected},
+				{"empty section", `{"affiliation-group":[],"path":"/0000-0002-1825-0097/` + s.section + `"}`, []ORCIDAffiliation{}},
+				{"null section", `{"affiliation-group":null}`, []ORCIDAffiliation{}},
+			}
+
+			for _, b := range bodies {
+				t.Run(b.name, func(t *testing.T) {
+					srv := newTestORCIDServer(t)
+					srv.setResponse("/v3.0/"+testORCIDServeriD+"/"+s.section, http.StatusOK, b.body)
+
+					affiliations, err := s.fetch(srv.provider(), srv.token())
+					if err != nil {
+						t.Fatalf("Expected nil error, got %v", err)
+					}
+
+					if !reflect.DeepEqual(affiliations, b.expected) {
+						t.Fatalf("Expected %s\n%#v\ngot\n%#v", s.section, b.expected, affiliations)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestORCIDFetchResearchResources(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		body     string
+		expected []ORCIDResearchResource
+	}{
+		{
+			"multiple research resources",
+			testORCIDResearchResourcesJSON,
+			[]ORCIDResearchResource{
+				{
+					PutCode:   9001,
+					Title:     "Kiln Time for Psychoceramic Samples",
+					Hosts:     []string{"Brown University Kiln Facility", "Rhode Island School of Design"},
+					StartDate: &ORCIDDate{Year: 2019, Month: 3},
+					EndDate:   &ORCIDDate{Year: 2019, Month: 9},
+					ExternalIds: []ORCIDExternalIdentifier{
+						{Type: "proposal-id", Value: "KILN-2019-42"},
+					},
+					Visibility: ORCIDVisibilityPublic,
+					Source:     &ORCIDSource{Name: "Brown University", ClientId: "APP-1234567890ABCDEF"},
+				},
+				{
+					PutCode:     9003,
+					Title:       "Beamtime for Ceramic Cracking Analysis",
+					Hosts:       []string{"European Synchrotron Radiation Facility"},
+					StartDate:   &ORCIDDate{Year: 2021},
+					ExternalIds: []ORCIDExternalIdentifier{},
+					Visibility:  ORCIDVisibilityLimited,
+				},
+			},
+		},
+		{
+			"empty research resources",
+			`{"group":[]}`,
+			[]ORCIDResearchResource{},
+		},
+		{
+			"null research resources",
+			`{"group":null}`,
+			[]ORCIDResearchResource{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/research-resources", s.body)
+			defer cleanup()
+
+			resources, err := p.FetchResearchResources(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(resources, s.expected) {
+				t.Fatalf("Expected research resources\n%#v\ngot\n%#v", s.expected, resources)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchPeerReviews(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		body     string
+		expected []ORCIDPeerReviewGroup
+	}{
+		{
+			"multiple review groups",
+			testORCIDPeerReviewsJSON,
+			[]ORCIDPeerReviewGroup{
+				{
+					GroupId:            "issn:0264-3561",
+					Organization:       "Psychoceramics Press",
+					Roles:              []string{"reviewer", "editor"},
+					Count:              3,
+					LastCompletionDate: &ORCIDDate{Year: 2018, Month: 11, Day: 2},
+				},
+				{
+					GroupId:            "orcid-generated:ceramics-conf",
+					Organization:       "International Conference on Psychoceramics",
+					Roles:              []string{"chair"},
+					Count:              1,
+					LastCompletionDate: &ORCIDDate{Year: 2012},
+				},
+			},
+		},
+		{
+			"empty peer reviews",
+			`{"group":[]}`,
+			[]ORCIDPeerReviewGroup{},
+		},
+		{
+			"null peer reviews",
+			`{"group":null}`,
+			[]ORCIDPeerReviewGroup{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/peer-reviews", s.body)
+			defer cleanup()
+
+			groups, err := p.FetchPeerReviews(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(groups, s.expected) {
+				t.Fatalf("Expected peer review groups\n%#v\ngot\n%#v", s.expected, groups)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchPeerReviewsAPIVersion21(t *testing.T) {
+	body := `{
+		"last-modified-date": {"value": 1704067200000},
+		"group": [
+			{
+				"external-ids": {"external-id": [{"external-id-type": "peer-review", "external-id-value": "issn:0264-3561"}]},
+				"peer-review-summary": [
+					{
+						"put-code": 10001,
+						"reviewer-role": "REVIEWER",
+						"review-group-id": "issn:0264-3561",
+						"completion-date": {"year": {"value": "2015"}, "month": {"value": "04"}, "day": null},
+						"convening-organization": {"name": "Psychoceramics Press"},
+						"visibility": "PUBLIC"
+					},
+					{
+						"put-code": 10003,
+						"reviewer-role": "EDITOR",
+						"review-group-id": "issn:0264-3561",
+						"completion-date": {"year": {"value": "2018"}, "month": {"value": "11"}, "day": {"value": "02"}},
+						"convening-organization": {"name": "Psychoceramics Press"},
+						"visibility": "PUBLIC"
+					}
+				]
+			},
+			{
+				"external-ids": {"external-id": [{"external-id-type": "peer-review", "external-id-value": "orcid-generated:ceramics-conf"}]},
+				"peer-review-summary": [
+					{
+						"put-code": 10005,
+						"reviewer-role": "CHAIR",
+						"review-group-id": "orcid-generated:ceramics-conf",
+						"completion-date": {"year": {"value": "2012"}, "month": null, "day": null},
+						"convening-organization": {"name": "International Conference on Psychoceramics"},
+						"visibility": "LIMITED"
+					}
+				]
+			},
+			{
+				"peer-review-summary": []
+			}
+		],
+		"path": "/0000-0002-1825-0097/peer-reviews"
+	}`
+
+	srv := newTestORCIDServer(t)
+	srv.setResponse("/v2.1/"+testORCIDServeriD+"/peer-reviews", http.StatusOK, body)
+
+	groups, err := srv.provider(WithORCIDAPIVersion(ORCIDAPIVersion21)).FetchPeerReviews(srv.token())
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	expected := []ORCIDPeerReviewGroup{
+		{
+			GroupId:            "issn:0264-3561",
+			Organization:       "Psychoceramics Press",
+			Roles:              []string{"reviewer", "editor"},
+			Count:              2,
+			LastCompletionDate: &ORCIDDate{Year: 2018, Month: 11, Day: 2},
+		},
+		{
+			GroupId:            "orcid-generated:ceramics-conf",
+			Organization:       "International Conference on Psychoceramics",
+			Roles:              []string{"chair"},
+			Count:              1,
+			LastCompletionDate: &ORCIDDate{Year: 2012},
+		},
+	}
+
+	if !reflect.DeepEqual(groups, expected) {
+		t.Fatalf("Expected peer review groups\n%#v\ngot\n%#v", expected, groups)
+	}
+}
+
+func TestORCIDFetchWorks(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		body     string
+		expected []ORCIDWork
+	}{
+		{
+			"multiple works",
+			testORCIDWorksJSON,
+			[]ORCIDWork{
+				{
+					PutCode:         3001,
+					Title:           "Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory",
+					Type:            "journal-article",
+					PublicationDate: &ORCIDDate{Year: 2008, Month: 8, Day: 13},
+					Journal:         "Journal of Psychoceramics",
+					DOI:             "10.5555/12345678",
+					ExternalIds: []ORCIDExternalIdentifier{
+						{Type: "doi", Value: "10.5555/12345678", URL: "https://doi.org/10.5555/12345678"},
+						{Type: "issn", Value: "0264-3561"},
+					},
+					Visibility: ORCIDVisibilityPublic,
+				},
+				{
+					PutCode:         3003,
+					Title:           "The Psychoceramics Handbook",
+					Type:            "book-chapter",
+					PublicationDate: &ORCIDDate{Year: 1995},
+					ExternalIds:     []ORCIDExternalIdentifier{},
+					Visibility:      ORCIDVisibilityPublic,
+				},
+			},
+		},
+		{
+			"empty works",
+			`{"group":[]}`,
+			[]ORCIDWork{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/works", s.body)
+			defer cleanup()
+
+			works, err := p.FetchWorks(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if !reflect.DeepEqual(works, s.expected) {
+				t.Fatalf("Expected works\n%#v\ngot\n%#v", s.expected, works)
+			}
+		})
+	}
+}
+
+func TestORCIDWorkDOIs(t *testing.T) {
+	doi := func(value string) ORCIDExternalIdentifier {
+		return ORCIDExternalIdentifier{Type: "doi", Value: value}
+	}
+
+	works := []ORCIDWork{
+		{Title: "a", ExternalIds: []ORCIDExternalIdentifier{doi("10.5555/ABC")}},
+		{Title: "no doi", ExternalIds: []ORCIDExternalIdentifier{{Type: "isbn", Value: "978-3-16-148410-0"}}},
+		{Title: "b", ExternalIds: []ORCIDExternalIdentifier{doi(" https://doi.org/10.5555/Def ")}},
+		{Title: "duplicate", ExternalIds: []ORCIDExternalIdentifier{doi("http://dx.doi.org/10.5555/abc")}},
+		{Title: "c", ExternalIds: []ORCIDExternalIdentifier{doi("doi:10.1000/XYZ.1"), doi("DOI.ORG/10.1000/xyz.1")}},
+		{Title: "d", ExternalIds: []ORCIDExternalIdentifier{{Type: "DOI", Value: "https://DX.DOI.ORG/10.1234/Mixed(Case)"}}},
+		{Title: "invalid", ExternalIds: []ORCIDExternalIdentifier{doi("not-a-doi"), doi("10.5555")}},
+		{Title: "empty"},
+	}
+
+	expected := []string{"10.5555/abc", "10.5555/def", "10.1000/xyz.1", "10.1234/mixed(case)"}
+
+	dois := ORCIDWorkDOIs(works)
+
+	if !slices.Equal(dois, expected) {
+		t.Fatalf("Expected DOIs %v, got %v", expected, dois)
+	}
+
+	if v := ORCIDWorkDOIs(nil); v == nil || len(v) != 0 {
+		t.Fatalf("Expected empty non-nil DOIs, got %#v", v)
+	}
+}
+
+func TestORCIDPublicWorksCount(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"multiple works", testORCIDWorksJSON, 2},
+		{"empty works", `{"group":[]}`, 0},
+		{
+			"mixed visibility",
+			`{"group":[
+				{"work-summary":[{"put-code":1,"visibility":"public"},{"put-code":2,"visibility":"limited"}]},
+				{"work-summary":[{"put-code":3,"visibility":"limited"}]},
+				{"work-summary":[]},
+				{"work-summary":[{"put-code":4,"visibility":"PUBLIC"}]},
+				{"work-summary":[{"put-code":5,"visibility":"private"}]},
+				{"work-summary":[{"put-code":6,"visibility":"public"}]}
+			]}`,
+			3,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/works", s.body)
+			defer cleanup()
+
+			count, err := p.PublicWorksCount(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if count != s.expected {
+				t.Fatalf("Expected count %d, got %d", s.expected, count)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchFundings(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/fundings", testORCIDFundingsJSON)
+	defer cleanup()
+
+	fundings, err := p.FetchFundings(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	expected := []ORCIDFunding{
+		{
+			PutCode:      4001,
+			Title:        "Psychoceramics of Cracked Pots",
+			Type:         "grant",
+			Organization: "National Science Foundation",
+			StartDate:    &ORCIDDate{Year: 2010, Month: 1},
+			EndDate:      &ORCIDDate{Year: 2013, Month: 12},
+			Amount:       "150000",
+			Currency:     "USD",
+		},
+		{
+			PutCode:      4002,
+			Title:        "Travel award",
+			Type:         "award",
+			Organization: "Brown University",
+			StartDate:    &ORCIDDate{Year: 2015},
+		},
+	}
+
+	if !reflect.DeepEqual(fundings, expected) {
+		t.Fatalf("Expected fundings\n%#v\ngot\n%#v", expected, fundings)
+	}
+}
+
+// testORCIDSectionProvider returns an ORCID provider and token configured
+// against a test server that responds with the provided body at the specified path.
+func testORCIDSectionProvider(t *testing.T, path string, body string) (*ORCID, *oauth2.Token, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"response-code":404,"developer-message":"unexpected path %s"}`, r.URL.Path)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	return p, token, srv.Close
+}
+
+func TestORCIDFetchWorkDetail(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/work/3001", testORCIDWorkDetailJSON)
+	defer cleanup()
+
+	t.Run("invalid put-code", func(t *testing.T) {
+		for _, putCode := range []string{"", "abc", "0", "00", "-1", "1/../2", "3001?x=1"} {
+			if _, err := p.FetchWorkDetail(token, putCode); err == nil || !strings.Contains(err.Error(), "put-code") {
+				t.Fatalf("Expected put-code error for %q, got %v", putCode, err)
+			}
+		}
+	})
+
+	t.Run("work detail", func(t *testing.T) {
+		detail, err := p.FetchWorkDetail(token, "3001")
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+
+		expected := &ORCIDWorkDetail{
+			ORCIDWork: ORCIDWork{
+				PutCode:         3001,
+				Title:           "Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory",
+				Type:            "journal-article",
+				PublicationDate: &ORCIDDate{Year: 2008, Month: 8, Day: 13},
+				Journal:         "Journal of Psychoceramics",
+				DOI:             "10.5555/12345678",
+				ExternalIds: []ORCIDExternalIdentifier{
+					{Type: "doi", Value: "10.5555/12345678", URL: "https://doi.org/10.5555/12345678"},
+				},
+				Visibility: ORCIDVisibilityPublic,
+			},
+			Subtitle:         "A Psychoceramics Approach",
+			ShortDescription: "The silly string theory is introduced.",
+			URL:              "https://doi.org/10.5555/12345678",
+			LanguageCode:     "en",
+			CitationType:     "bibtex",
+			CitationValue:    "@article{carberry2008,title={Toward a Unified Theory of High-Energy Metaphysics: Silly String Theory},author={Carberry, Josiah},journal={Journal of Psychoceramics},year={2008}}",
+			Contributors: []ORCIDContributor{
+				{Name: "Josiah Carberry", ORCID: "0000-0002-1825-0097", Sequence: "first", Role: "author"},
+				{Name: "Truman Grayson", Sequence: "additional", Role: "author"},
+			},
+		}
+
+		if !reflect.DeepEqual(detail, expected) {
+			t.Fatalf("Expected work detail\n%#v\ngot\n%#v", expected, detail)
+		}
+	})
+
+	t.Run("missing work", func(t *testing.T) {
+		_, err := p.FetchWorkDetail(token, "9999")
+
+		var apiErr *ORCIDAPIError
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+			t.Fatalf("Expected 404 ORCIDAPIError, got %v", err)
+		}
+	})
+}
+
+// testORCIDManyWorksJSON generates a /works response with n works groups (put-codes 1..n).
+func testORCIDManyWorksJSON(n int) string {
+	groups := make([]string, n)
+	for i := range groups {
+		groups[i] = fmt.Sprintf(`{"work-summary":[{"put-code":%d,"title":{"title":{"value":"Work %d"}},"type":"journal-article"}]}`, i+1, i+1)
+	}
+
+	return `{"group":[` + strings.Join(groups, ",") + `]}`
+}
+
+func TestORCIDFetchWorksPage(t *testing.T) {
+	var worksRequests, detailRequests, inflight, maxInflight atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/v3.0/0000-0002-1825-0097/works" {
+			worksRequests.Add(1)
+			fmt.Fprint(w, testORCIDManyWorksJSON(300))
+			return
+		}
+
+		putCode, ok := strings.CutPrefix(r.URL.Path, "/v3.0/0000-0002-1825-0097/work/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		detailRequests.Add(1)
+
+		current := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			prev := maxInflight.Load()
+			if current <= prev || maxInflight.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if putCode == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, `{"put-code":%s,"title":{"title":{"value":"Work %s"}},"type":"journal-article","contributors":{"contributor":[]}}`, putCode, putCode)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.BatchConcurrency = 3
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	var putCodes []int64
+	for offset := 0; ; offset += 50 {
+		page, err := p.FetchWorksPage(token, offset, 50)
+		if err != nil {
+			t.Fatalf("[%d] Expected nil error, got %v", offset, err)
+		}
+
+		if page.Total != 300 {
+			t.Fatalf("[%d] Expected total 300, got %d", offset, page.Total)
+		}
+
+		if len(page.Works) == 0 {
+			break
+		}
+
+		if len(page.Works) != 50 {
+			t.Fatalf("[%d] Expected 50 works, got %d", offset, len(page.Works))
+		}
+
+		for _, w := range page.Works {
+			putCodes = append(putCodes, w.PutCode)
+		}
+	}
+
+	if len(putCodes) != 300 || putCodes[0] != 1 || putCodes[299] != 300 {
+		t.Fatalf("Expected put-codes 1..300, got %d items", len(putCodes))
+	}
+
+	page, err := p.FetchWorksPageContext(context.Background(), token, 250, 100)
+	if err != nil || len(page.Works) != 50 || page.Works[0].PutCode != 251 {
+		t.Fatalf("Expected the last 50 works, got %v (%v)", page, err)
+	}
+
+	// without ConditionalRequests every page call downloads the full /works again
+	if n := worksRequests.Load(); n != 8 {
+		t.Fatalf("Expected 8 /works requests, got %d", n)
+	}
+
+	details, err := p.FetchWorkDetails(token, page.Works)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if len(details) != 50 {
+		t.Fatalf("Expected 50 details, got %d", len(details))
+	}
+
+	for i, d := range details {
+		if d.PutCode != page.Works[i].PutCode || d.Title != page.Works[i].Title {
+			t.Fatalf("[%d] Expected details in the works order, got %d %q", i, d.PutCode, d.Title)
+		}
+	}
+
+	if n := detailRequests.Load(); n != 50 {
+		t.Fatalf("Expected 50 detail requests, got %d", n)
+	}
+
+	if n := maxInflight.Load(); n > 3 {
+		t.Fatalf("Expected at most 3 concurrent detail requests, got %d", n)
+	}
+
+	t.Run("failed detail", func(t *testing.T) {
+		_, err := p.FetchWorkDetailsContext(context.Background(), token, []ORCIDWork{{PutCode: 1}, {PutCode: 404}, {PutCode: 2}})
+
+		var apiErr *ORCIDAPIError
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+			t.Fatalf("Expected 404 ORCIDAPIError, got %v", err)
+		}
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		if _, err := p.FetchWorksPage(token, -1, 10); err == nil {
+			t.Fatal("Expected negative offset error")
+		}
+
+		if _, err := p.FetchWorksPage(token, 0, 0); err == nil {
+			t.Fatal("Expected zero limit error")
+		}
+	})
+}
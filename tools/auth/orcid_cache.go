@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/store"
+	"golang.org/x/oauth2"
+)
+
+// ORCIDCache defines a pluggable key-value cache backend shared by the
+// ORCID person, conditional responses and client credentials token caches.
+//
+// Implementations must be safe for concurrent use and could be backed
+// by memory, Redis, the PocketBase store, etc.
+type ORCIDCache interface {
+	// Get returns the cached value of the specified key
+	// (false if it is missing or has expired).
+	Get(key string) ([]byte, bool)
+
+	// Set stores the value of the specified key for the ttl duration
+	// (ttl <= 0 means that the value doesn't expire).
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Delete removes the specified key from the cache.
+	Delete(key string)
+}
+
+// orcidDefaultCache is the in-memory cache used by all providers without explicit Cache.
+var orcidDefaultCache = newORCIDMemoryCache()
+
+// NewORCIDMemoryCache creates a new in-memory [ORCIDCache].
+func NewORCIDMemoryCache() ORCIDCache {
+	return newORCIDMemoryCache()
+}
+
+func newORCIDMemoryCache() *orcidMemoryCache {
+	return &orcidMemoryCache{
+		store: store.New[string, orcidMemoryCacheItem](nil),
+	}
+}
+
+type orcidMemoryCacheItem struct {
+	expires time.Time // zero for no expiration
+	value   []byte
+}
+
+type orcidMemoryCache struct {
+	store *store.Store[string, orcidMemoryCacheItem]
+}
+
+// Get implements [ORCIDCache.Get].
+func (c *orcidMemoryCache) Get(key string) ([]byte, bool) {
+	item, ok := c.store.GetOk(key)
+	if !ok {
+		return nil, false
+	}
+
+	if !item.expires.IsZero() && !time.Now().Before(item.expires) {
+		c.store.Remove(key)
+		return nil, false
+	}
+
+	return item.value, true
+}
+
+// Set implements [ORCIDCache.Set].
+func (c *orcidMemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	item := orcidMemoryCacheItem{value: value}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+
+	c.store.Set(key, item)
+}
+
+// Delete implements [ORCIDCache.Delete].
+func (c *orcidMemoryCache) Delete(key string) {
+	c.store.Remove(key)
+}
+
+// deletePrefix removes all keys with the specified prefix.
+func (c *orcidMemoryCache) deletePrefix(prefix string) {
+	for key := range c.store.GetAll() {
+		if strings.HasPrefix(key, prefix) {
+			c.store.Remove(key)
+		}
+	}
+}
+
+// cache returns the provider Cache or the shared default in-memory cache if not set.
+func (p *ORCID) cache() ORCIDCache {
+	if p.Cache == nil {
+		return orcidDefaultCache
+	}
+
+	return p.Cache
+}
+
+// Cache key prefixes of the different ORCID cached values.
+const (
+	orcidCachePrefixPerson   = "orcid_person:"
+	orcidCachePrefixResponse = "orcid_response:"
+	orcidCachePrefixToken    = "orcid_token:"
+)
+
+// orcidCachedResponse defines a single cached ORCID API response
+// used for the conditional (If-None-Match/If-Modified-Since) requests.
+type orcidCachedResponse struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+	Body         []byte `json:"body"`
+}
+
+// cachedResponse returns the conditional response of the specified
+// responseCacheKey stored in the provider cache (if any).
+func (p *ORCID) cachedResponse(key string) *orcidCachedResponse {
+	raw, ok := p.cache().Get(orcidCachePrefixResponse + key)
+	if !ok {
+		return nil
+	}
+
+	response := &orcidCachedResponse{}
+	if err := json.Unmarshal(raw, response); err != nil {
+		return nil
+	}
+
+	return response
+}
+
+// setCachedResponse stores the conditional response of the specified
+// responseCacheKey in the provider cache.
+//
+// The responses don't expire since they are always revalidated with
+// a conditional request.
+func (p *ORCID) setCachedResponse(key string, response *orcidCachedResponse) {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	p.cache().Set(orcidCachePrefixResponse+key, raw, 0)
+}
+
+// FlushORCIDPersonCache removes all cached ORCID person responses
+// from the default in-memory cache (aka. of the providers without explicit Cache).
+func FlushORCIDPersonCache() {
+	orcidDefaultCache.deletePrefix(orcidCachePrefixPerson)
+}
+
+type orcidParsedPerson struct {
+	rawUser map[string]any
+	name    string
+	email   string
+}
+
+// orcidCachedPerson defines the cached person data from which
+// an [orcidParsedPerson] is reconstructed on cache hit.
+type orcidCachedPerson struct {
+	Person json.RawMessage `json:"person"`
+	Locale string          `json:"locale,omitempty"`
+
+	// Created is the record creation time in epoch millis (0 if unknown).
+	Created int64 `json:"created,omitempty"`
+
+	// Emails are the member API fallback emails (if any).
+	Emails []ORCIDEmail `json:"emails,omitempty"`
+}
+
+// ORCID token access classes used to separate the person cache entries.
+const (
+	// orcidTokenClassPublic is the class of the tokens that can read only
+	// the public record data (eg. the "/read-public" client credentials tokens).
+	orcidTokenClassPublic = "public"
+
+	// orcidTokenClassReadLimited is the class of the user tokens that
+	// can read also the limited visibility record data.
+	orcidTokenClassReadLimited = "read-limited"
+)
+
+// orcidTokenClass returns the access class of the specified user token
+// based on its "/read-limited" scope (see [checkORCIDTokenScope]).
+func orcidTokenClass(token *oauth2.Token) string {
+	if checkORCIDTokenScope(token, ORCIDScopeReadLimited) == nil {
+		return orcidTokenClassReadLimited
+	}
+
+	return orcidTokenClassPublic
+}
+
+// personCacheKey returns the person cache key of the specified iD
+// and token access class.
+//
+// It includes the provider settings that affect the parsed result.
+func (p *ORCID) personCacheKey(iD string, tokenClass string) string {
+	section := "/person"
+	if p.UseRecord {
+		section = "/record"
+	}
+
+	return orcidCachePrefixPerson + p.apiURL(iD, section) + "|" +
+		strconv.FormatBool(p.StrictEmail) + "|" +
+		strconv.FormatBool(p.member && !p.SkipEmail) + "|" +
+		p.MemberAPIBaseURL + "|" +
+		tokenClass
+}
+
+// responseCacheKey returns the conditional response cache key of the
+// specified url and token.
+//
+// Similar to the fetchJSON deduplication key it includes the AcceptLanguage
+// and the token access class. The "/read-limited" responses are further separated
+// by the token owner iD because the same url could return a different limited
+// visibility data depending on whose token is used.
+func (p *ORCID) responseCacheKey(token *oauth2.Token, url string) string {
+	scope := orcidTokenClass(token)
+	if scope == orcidTokenClassReadLimited {
+		iD, _ := orcidTokenId(token)
+		scope += ":" + iD
+	}
+
+	return url + "|" + p.AcceptLanguage + "|" + scope
+}
@@ -0,0 +1,518 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestORCIDResponseCacheNotModified(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+
+	var totalRequests, totalNotModified atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests.Add(1)
+
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			totalNotModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.Cache = NewORCIDMemoryCache()
+	p.ConditionalRequests = true
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	for i := 0; i < 3; i++ {
+		user, err := p.FetchAuthUser(token)
+		if err != nil {
+			t.Fatalf("[%d] Expected nil error, got %v", i, err)
+		}
+
+		if user.Name != "Josiah" {
+			t.Fatalf("[%d] Expected name %q, got %q", i, "Josiah", user.Name)
+		}
+	}
+
+	if total := totalRequests.Load(); total != 3 {
+		t.Fatalf("Expected 3 requests, got %d", total)
+	}
+
+	if total := totalNotModified.Load(); total != 2 {
+		t.Fatalf("Expected 2 conditional requests, got %d", total)
+	}
+
+	cached := p.cachedResponse(p.responseCacheKey(token, p.apiURL("0000-0002-1825-0097", "/person")))
+	if cached == nil || cached.ETag != etag || cached.LastModified != lastModified {
+		t.Fatalf("Expected cached response with ETag %q and Last-Modified %q, got %v", etag, lastModified, cached)
+	}
+}
+
+func TestORCIDResponseCacheWithoutValidators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.Cache = NewORCIDMemoryCache()
+	p.ConditionalRequests = true
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	if _, err := p.FetchAuthUser(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if cached := p.cachedResponse(p.responseCacheKey(token, p.apiURL("0000-0002-1825-0097", "/person"))); cached != nil {
+		t.Fatal("Expected responses without ETag and Last-Modified to not be cached")
+	}
+}
+
+func TestORCIDResponseCacheTokenClass(t *testing.T) {
+	const etag = `"v1"`
+
+	var totalNotModified atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a naive server that revalidates any matching ETag regardless of the token
+		if r.Header.Get("If-None-Match") == etag {
+			totalNotModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("Authorization") == "Bearer member" {
+			fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[{"email":"josiah.limited@example.com","visibility":"limited","verified":true,"primary":true}]}}`)
+		} else {
+			fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[]}}`)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.Cache = NewORCIDMemoryCache()
+	p.ConditionalRequests = true
+
+	memberToken := (&oauth2.Token{AccessToken: "member"}).WithExtra(map[string]any{
+		"orcid": "0000-0002-1825-0097",
+		"scope": ORCIDScopeAuthenticate + " " + ORCIDScopeReadLimited,
+	})
+	publicToken := (&oauth2.Token{AccessToken: "public"}).WithExtra(map[string]any{
+		"orcid": "0000-0002-1825-0097",
+		"scope": ORCIDScopeAuthenticate,
+	})
+
+	member, err := p.FetchAuthUser(memberToken)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if member.Email != "josiah.limited@example.com" {
+		t.Fatalf("Expected the member limited email, got %q", member.Email)
+	}
+
+	public, err := p.FetchAuthUser(publicToken)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if public.Email != "" {
+		t.Fatalf("Expected the public token to not receive the cached limited email, got %q", public.Email)
+	}
+
+	if total := totalNotModified.Load(); total != 0 {
+		t.Fatalf("Expected no conditional requests across token classes, got %d", total)
+	}
+
+	// same token class
+	public, err = p.FetchAuthUser(publicToken)
+	if err != nil || public.Email != "" {
+		t.Fatalf("Expected the cached public response, got %v (%v)", public, err)
+	}
+
+	if total := totalNotModified.Load(); total != 1 {
+		t.Fatalf("Expected 1 conditional request with the same token class, got %d", total)
+	}
+}
+
+func TestORCIDPersonCache(t *testing.T) {
+	defer FlushORCIDPersonCache()
+
+	var totalRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[{"email":"josiah@example.com"}]}}`)
+	}))
+	defer srv.Close()
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	fetch := func(p *ORCID) *AuthUser {
+		user, err := p.FetchAuthUser(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user.Name != "Josiah" || user.Email != "josiah@example.com" {
+			t.Fatalf("Expected Josiah with josiah@example.com, got %q with %q", user.Name, user.Email)
+		}
+
+		return user
+	}
+
+	t.Run("disabled cache", func(t *testing.T) {
+		totalRequests.Store(0)
+
+		p := NewORCIDProvider()
+		p.APIBaseURL = srv.URL
+
+		fetch(p)
+		fetch(p)
+
+		if total := totalRequests.Load(); total != 2 {
+			t.Fatalf("Expected 2 requests, got %d", total)
+		}
+	})
+
+	t.Run("enabled cache", func(t *testing.T) {
+		totalRequests.Store(0)
+
+		p := NewORCIDProvider()
+		p.APIBaseURL = srv.URL
+		p.PersonCacheTTL = time.Minute
+
+		user := fetch(p)
+		user.RawUser["biography"] = "changed" // shouldn't affect the cached value
+
+		// new provider instance with the same configuration
+		p2 := NewORCIDProvider()
+		p2.APIBaseURL = srv.URL
+		p2.PersonCacheTTL = time.Minute
+
+		user2 := fetch(p2)
+		if v := user2.RawUser["biography"]; v != "" {
+			t.Fatalf("Expected the cached RawUser to be unchanged, got biography %v", v)
+		}
+
+		if total := totalRequests.Load(); total != 1 {
+			t.Fatalf("Expected 1 request, got %d", total)
+		}
+
+		FlushORCIDPersonCache()
+
+		fetch(p)
+
+		if total := totalRequests.Load(); total != 2 {
+			t.Fatalf("Expected 2 requests after flush, got %d", total)
+		}
+	})
+
+	t.Run("expired cache", func(t *testing.T) {
+		FlushORCIDPersonCache()
+		totalRequests.Store(0)
+
+		p := NewORCIDProvider()
+		p.APIBaseURL = srv.URL
+		p.PersonCacheTTL = time.Millisecond
+
+		fetch(p)
+		time.Sleep(5 * time.Millisecond)
+		fetch(p)
+
+		if total := totalRequests.Load(); total != 2 {
+			t.Fatalf("Expected 2 requests, got %d", total)
+		}
+	})
+}
+
+func TestORCIDPersonCacheTokenClass(t *testing.T) {
+	const limitedEmail = "josiah.limited@example.com"
+
+	memberToken := func(srv *testORCIDServer) *oauth2.Token {
+		return srv.token().WithExtra(map[string]any{
+			"orcid": testORCIDServeriD,
+			"scope": ORCIDScopeAuthenticate + " " + ORCIDScopeReadLimited,
+		})
+	}
+
+	newProvider := func(t *testing.T) (*testORCIDServer, *ORCID) {
+		srv := newTestORCIDServer(t)
+
+		// the limited email is available only through the member /email fallback
+		srv.setResponse("/v3.0/"+testORCIDServeriD+"/person", http.StatusOK, `{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[]}}`)
+
+		return srv, srv.provider(WithORCIDMember(), WithORCIDCache(NewORCIDMemoryCache(), time.Minute))
+	}
+
+	t.Run("member login before public lookup", func(t *testing.T) {
+		srv, p := newProvider(t)
+
+		user, err := p.FetchAuthUser(memberToken(srv))
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if user.Email != limitedEmail {
+			t.Fatalf("Expected member login email %q, got %q", limitedEmail, user.Email)
+		}
+
+		public, err := p.FetchPublicPerson(context.Background(), testORCIDServeriD)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if public.Email != "" {
+			t.Fatalf("Expected the public lookup to not return the limited email, got %q", public.Email)
+		}
+	})
+
+	t.Run("public lookup before member login", func(t *testing.T) {
+		srv, p := newProvider(t)
+
+		public, err := p.FetchPublicPerson(context.Background(), testORCIDServeriD)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if public.Email != "" {
+			t.Fatalf("Expected empty public lookup email, got %q", public.Email)
+		}
+
+		user, err := p.FetchAuthUser(memberToken(srv))
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if user.Email != limitedEmail {
+			t.Fatalf("Expected member login email %q, got %q", limitedEmail, user.Email)
+		}
+
+		// the second member login is served from the cache
+		srv.setResponse("/v3.0/"+testORCIDServeriD+"/email", http.StatusInternalServerError, "")
+
+		user, err = p.FetchAuthUser(memberToken(srv))
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+		if user.Email != limitedEmail {
+			t.Fatalf("Expected cached member login email %q, got %q", limitedEmail, user.Email)
+		}
+	})
+}
+
+func TestORCIDMemoryCache(t *testing.T) {
+	cache := NewORCIDMemoryCache()
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatal("Expected missing key to not be found")
+	}
+
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), time.Minute)
+	cache.Set("c", []byte("3"), time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if v, ok := cache.Get("a"); !ok || string(v) != "1" {
+		t.Fatalf("Expected non-expiring a=1, got %q (%v)", v, ok)
+	}
+
+	if v, ok := cache.Get("b"); !ok || string(v) != "2" {
+		t.Fatalf("Expected b=2, got %q (%v)", v, ok)
+	}
+
+	if _, ok := cache.Get("c"); ok {
+		t.Fatal("Expected c to be expired")
+	}
+
+	cache.Delete("a")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Expected a to be deleted")
+	}
+}
+
+// testORCIDStubCache is an [ORCIDCache] stub that records its calls.
+type testORCIDStubCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	gets   int
+}
+
+func newTestORCIDStubCache() *testORCIDStubCache {
+	return &testORCIDStubCache{
+		values: map[string][]byte{},
+		ttls:   map[string]time.Duration{},
+	}
+}
+
+func (c *testORCIDStubCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gets++
+	v, ok := c.values[key]
+
+	return v, ok
+}
+
+func (c *testORCIDStubCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = value
+	c.ttls[key] = ttl
+}
+
+func (c *testORCIDStubCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+	delete(c.ttls, key)
+}
+
+func (c *testORCIDStubCache) keys(prefix string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []string
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func TestORCIDCustomCache(t *testing.T) {
+	cache := newTestORCIDStubCache()
+
+	srv := newTestORCIDServer(t)
+
+	t.Run("person cache", func(t *testing.T) {
+		p := srv.provider(WithORCIDCache(cache, time.Minute))
+
+		user1, err := p.FetchAuthUser(srv.token())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys := cache.keys(orcidCachePrefixPerson)
+		if len(keys) != 1 || cache.ttls[keys[0]] != time.Minute {
+			t.Fatalf("Expected a single person entry with 1m ttl, got %v (%v)", keys, cache.ttls)
+		}
+
+		// a new provider instance with the same cache backend
+		user2, err := srv.provider(WithORCIDCache(cache, time.Minute)).FetchAuthUser(srv.token())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user1.Name != user2.Name || user1.Email != user2.Email || !reflect.DeepEqual(user1.RawUser, user2.RawUser) {
+			t.Fatalf("Expected the cached user to match\n%#v\ngot\n%#v", user1, user2)
+		}
+
+		personPaths := 0
+		for _, path := range srv.requestedPaths() {
+			if strings.HasSuffix(path, "/person") {
+				personPaths++
+			}
+		}
+		if personPaths != 1 {
+			t.Fatalf("Expected 1 person request, got %d", personPaths)
+		}
+
+		// the custom cache entries are not affected by the default cache flush
+		FlushORCIDPersonCache()
+		if len(cache.keys(orcidCachePrefixPerson)) != 1 {
+			t.Fatal("Expected the custom cache person entry to remain")
+		}
+	})
+
+	t.Run("client credentials token cache", func(t *testing.T) {
+		srv.setResponse("/oauth/token", http.StatusOK, `{"access_token":"client_token","token_type":"bearer","expires_in":3600,"scope":"/read-public"}`)
+
+		p := srv.provider(WithORCIDCache(cache, 0))
+
+		for i := 0; i < 2; i++ {
+			token, err := p.ClientCredentialsToken(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != "client_token" {
+				t.Fatalf("Expected client_token, got %q", token.AccessToken)
+			}
+		}
+
+		keys := cache.keys(orcidCachePrefixToken)
+		if len(keys) != 1 {
+			t.Fatalf("Expected a single token entry, got %v", keys)
+		}
+
+		if ttl := cache.ttls[keys[0]]; ttl <= 0 || ttl > time.Hour-orcidClientTokenExpiryDelta {
+			t.Fatalf("Expected the token ttl to be limited by its expiry, got %v", ttl)
+		}
+	})
+
+	t.Run("conditional responses", func(t *testing.T) {
+		var totalNotModified atomic.Int32
+
+		etagSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				totalNotModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+		}))
+		defer etagSrv.Close()
+
+		for i := 0; i < 2; i++ {
+			// a new provider instance with the same cache backend
+			p := NewORCIDProvider(WithORCIDCache(cache, 0), WithORCIDConditionalRequests())
+			p.APIBaseURL = etagSrv.URL
+
+			user, err := p.FetchAuthUser(srv.token())
+			if err != nil || user.Name != "Josiah" {
+				t.Fatalf("[%d] Expected user Josiah, got %v (%v)", i, user, err)
+			}
+		}
+
+		if keys := cache.keys(orcidCachePrefixResponse); len(keys) != 1 {
+			t.Fatalf("Expected a single response entry, got %v", keys)
+		}
+
+		if total := totalNotModified.Load(); total != 1 {
+			t.Fatalf("Expected 1 not modified response, got %d", total)
+		}
+	})
+}
@@ -0,0 +1,367 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// orcidBibTeXTypes maps the ORCID work types to the BibTeX entry types
+// (all other work types fallback to "misc").
+var orcidBibTeXTypes = map[string]string{
+	"journal-article":     "article",
+	"magazine-article":    "article",
+	"newspaper-article":   "article",
+	"book":                "book",
+	"edited-book":         "book",
+	"book-chapter":        "incollection",
+	"conference-paper":    "inproceedings",
+	"dissertation":        "phdthesis",
+	"dissertation-thesis": "phdthesis",
+	"report":              "techreport",
+	"working-paper":       "techreport",
+	"manual":              "manual",
+	"preprint":            "unpublished",
+}
+
+var orcidBibTeXMonths = [...]string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// ORCIDWorksToBibTeX converts the specified works into BibTeX entries.
+//
+// Works that already have a "bibtex" citation are exported verbatim.
+// All other works are mapped to a generated entry with "orcid{put-code}" key.
+//
+// Summaries returned by [ORCID.FetchWorks] can be exported by wrapping them
+// as ORCIDWorkDetail{ORCIDWork: work} (note that they don't have contributors).
+func ORCIDWorksToBibTeX(works []ORCIDWorkDetail) string {
+	entries := make([]string, 0, len(works))
+
+	for _, work := range works {
+		if strings.EqualFold(work.CitationType, "bibtex") && work.CitationValue != "" {
+			entries = append(entries, strings.TrimSpace(work.CitationValue)+"\n")
+			continue
+		}
+
+		entries = append(entries, orcidWorkToBibTeX(work))
+	}
+
+	return strings.Join(entries, "\n")
+}
+
+func orcidWorkToBibTeX(work ORCIDWorkDetail) string {
+	entryType, ok := orcidBibTeXTypes[work.Type]
+	if !ok {
+		entryType = "misc"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("@" + entryType + "{orcid" + strconv.FormatInt(work.PutCode, 10))
+
+	field := func(name string, value string) {
+		if value == "" {
+			return
+		}
+		sb.WriteString(",\n  " + name + " = " + value)
+	}
+
+	authors := orcidWorkAuthors(work.Contributors)
+	for i := range authors {
+		authors[i] = escapeBibTeX(authors[i])
+	}
+	if len(authors) > 0 {
+		field("author", "{"+strings.Join(authors, " and ")+"}")
+	}
+
+	title := work.Title
+	if work.Subtitle != "" {
+		title += ": " + work.Subtitle
+	}
+	if title != "" {
+		field("title", "{"+escapeBibTeX(title)+"}")
+	}
+
+	if work.Journal != "" {
+		switch entryType {
+		case "incollection", "inproceedings":
+			field("booktitle", "{"+escapeBibTeX(work.Journal)+"}")
+		default:
+			field("journal", "{"+escapeBibTeX(work.Journal)+"}")
+		}
+	}
+
+	if work.PublicationDate != nil {
+		field("year", "{"+strconv.Itoa(work.PublicationDate.Year)+"}")
+
+		if m := work.PublicationDate.Month; m >= 1 && m <= 12 {
+			field("month", orcidBibTeXMonths[m-1]) // month macros are not braced
+		}
+	}
+
+	// the doi and url fields are usually processed verbatim and therefore
+	// only the braces that could break the entry structure are removed
+	field("doi", bracedBibTeXVerbatim(work.DOI))
+	field("url", bracedBibTeXVerbatim(work.URL))
+
+	sb.WriteString("\n}\n")
+
+	return sb.String()
+}
+
+// orcidWorkAuthors returns the names of the work contributors
+// with "author" (or unspecified) role.
+func orcidWorkAuthors(contributors []ORCIDContributor) []string {
+	authors := make([]string, 0, len(contributors))
+
+	for _, c := range contributors {
+		if c.Name == "" || (c.Role != "" && c.Role != "author") {
+			continue
+		}
+		authors = append(authors, c.Name)
+	}
+
+	return authors
+}
+
+var bibTeXReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// escapeBibTeX escapes the LaTeX special characters of the specified field value.
+func escapeBibTeX(value string) string {
+	return bibTeXReplacer.Replace(value)
+}
+
+func bracedBibTeXVerbatim(value string) string {
+	value = strings.NewReplacer("{", "", "}", "").Replace(value)
+	if value == "" {
+		return ""
+	}
+
+	return "{" + value + "}"
+}
+
+// orcidCSLTypes maps the ORCID work types to the CSL item types
+// (all other work types fallback to "document").
+var orcidCSLTypes = map[string]string{
+	"journal-article":     "article-journal",
+	"magazine-article":    "article-magazine",
+	"newspaper-article":   "article-newspaper",
+	"book":                "book",
+	"edited-book":         "book",
+	"book-chapter":        "chapter",
+	"conference-paper":    "paper-conference",
+	"dissertation":        "thesis",
+	"dissertation-thesis": "thesis",
+	"report":              "report",
+	"working-paper":       "report",
+	"preprint":            "article",
+	"data-set":            "dataset",
+	"software":            "software",
+	"website":             "webpage",
+}
+
+// ORCIDCSLItem defines a single CSL-JSON item.
+//
+// See https://citeproc-js.readthedocs.io/en/latest/csl-json/markup.html
+type ORCIDCSLItem struct {
+	Id             string         `json:"id"`
+	Type           string         `json:"type"`
+	Title          string         `json:"title,omitempty"`
+	ContainerTitle string         `json:"container-title,omitempty"`
+	Author         []ORCIDCSLName `json:"author,omitempty"`
+	Issued         *ORCIDCSLDate  `json:"issued,omitempty"`
+	DOI            string         `json:"DOI,omitempty"`
+	URL            string         `json:"URL,omitempty"`
+	Abstract       string         `json:"abstract,omitempty"`
+	Language       string         `json:"language,omitempty"`
+}
+
+// ORCIDCSLName defines a CSL-JSON name variable.
+//
+// ORCID stores the contributors only as credit names and therefore
+// they are exported as literal (aka. not parsed) names.
+type ORCIDCSLName struct {
+	Literal string `json:"literal"`
+}
+
+// ORCIDCSLDate defines a CSL-JSON date variable, eg. {"date-parts":[[2020,1]]}.
+type ORCIDCSLDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// ORCIDWorksToCSL converts the specified works into CSL-JSON items.
+//
+// Missing work fields are omitted from the items.
+func ORCIDWorksToCSL(works []ORCIDWorkDetail) []ORCIDCSLItem {
+	items := make([]ORCIDCSLItem, 0, len(works))
+
+	for _, work := range works {
+		item := ORCIDCSLItem{
+			Id:             "orcid" + strconv.FormatInt(work.PutCode, 10),
+			Type:           orcidCSLTypes[work.Type],
+			Title:          work.Title,
+			ContainerTitle: work.Journal,
+			DOI:            work.DOI,
+			URL:            work.URL,
+			Abstract:       work.ShortDescription,
+			Language:       work.LanguageCode,
+		}
+
+		if item.Type == "" {
+			item.Type = "document"
+		}
+
+		if work.Subtitle != "" {
+			item.Title += ": " + work.Subtitle
+		}
+
+		for _, author := range orcidWorkAuthors(work.Contributors) {
+			item.Author = append(item.Author, ORCIDCSLName{Literal: author})
+		}
+
+		if d := work.PublicationDate; d != nil {
+			parts := []int{d.Year}
+			if d.Month > 0 {
+				parts = append(parts, d.Month)
+				if d.Day > 0 {
+					parts = append(parts, d.Day)
+				}
+			}
+			item.Issued = &ORCIDCSLDate{DateParts: [][]int{parts}}
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// ORCIDWorksToCSLJSON converts the specified works into an indented CSL-JSON array
+// (eg. for citation.js or Zotero imports).
+func ORCIDWorksToCSLJSON(works []ORCIDWorkDetail) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false) // titles commonly contain "&", "<" and ">"
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(ORCIDWorksToCSL(works)); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// orcidRISTypes maps the ORCID work types to the RIS reference types
+// (all other work types fallback to "GEN").
+var orcidRISTypes = map[string]string{
+	"journal-article":     "JOUR",
+	"magazine-article":    "MGZN",
+	"newspaper-article":   "NEWS",
+	"book":                "BOOK",
+	"edited-book":         "EDBOOK",
+	"book-chapter":        "CHAP",
+	"conference-paper":    "CPAPER",
+	"dissertation":        "THES",
+	"dissertation-thesis": "THES",
+	"report":              "RPRT",
+	"working-paper":       "RPRT",
+	"preprint":            "UNPB",
+	"patent":              "PAT",
+	"data-set":            "DATA",
+	"software":            "COMP",
+	"website":             "ELEC",
+}
+
+// ORCIDWorksToRIS converts the specified works into RIS records
+// (eg. for EndNote, Mendeley or Zotero imports).
+//
+// Works that already have a "ris" citation are exported verbatim.
+// All other works are mapped to a generated record with "orcid{put-code}" ID.
+func ORCIDWorksToRIS(works []ORCIDWorkDetail) string {
+	records := make([]string, 0, len(works))
+
+	for _, work := range works {
+		if strings.EqualFold(work.CitationType, "ris") && work.CitationValue != "" {
+			// only the surrounding new lines are trimmed to preserve the "ER  - " tag
+			records = append(records, strings.Trim(work.CitationValue, "\r\n")+"\n")
+			continue
+		}
+
+		records = append(records, orcidWorkToRIS(work))
+	}
+
+	return strings.Join(records, "\n")
+}
+
+func orcidWorkToRIS(work ORCIDWorkDetail) string {
+	refType, ok := orcidRISTypes[work.Type]
+	if !ok {
+		refType = "GEN"
+	}
+
+	var sb strings.Builder
+
+	tag := func(name string, value string) {
+		// the RIS tags are line based and cannot span multiple lines
+		value = strings.Join(strings.Fields(value), " ")
+		if value == "" {
+			return
+		}
+		sb.WriteString(name + "  - " + value + "\n")
+	}
+
+	tag("TY", refType)
+
+	for _, author := range orcidWorkAuthors(work.Contributors) {
+		tag("AU", author)
+	}
+
+	title := work.Title
+	if work.Subtitle != "" {
+		title += ": " + work.Subtitle
+	}
+	tag("TI", title)
+
+	switch refType {
+	case "CHAP", "CPAPER":
+		tag("T2", work.Journal)
+	default:
+		tag("JO", work.Journal)
+	}
+
+	if d := work.PublicationDate; d != nil {
+		tag("PY", strconv.Itoa(d.Year))
+
+		if d.Month > 0 {
+			// YYYY/MM/DD/other info, where the unknown parts are left empty
+			date := fmt.Sprintf("%04d/%02d/", d.Year, d.Month)
+			if d.Day > 0 {
+				date += fmt.Sprintf("%02d", d.Day)
+			}
+			tag("DA", date+"/")
+		}
+	}
+
+	tag("DO", work.DOI)
+	tag("UR", work.URL)
+	tag("AB", work.ShortDescription)
+	tag("LA", work.LanguageCode)
+	tag("ID", "orcid"+strconv.FormatInt(work.PutCode, 10))
+
+	sb.WriteString("ER  - \n")
+
+	return sb.String()
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the testdata golden files")
+
+// testORCIDGolden compares actual with the content of the specified testdata
+// golden file (run the tests with -update to regenerate it).
+func testORCIDGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != string(expected) {
+		t.Fatalf("Expected %s\n%s\ngot\n%s", name, expected, actual)
+	}
+}
+
+// testORCIDCitationWorks returns the works fixtures used by the citation export tests.
+func testORCIDCitationWorks(t *testing.T) []ORCIDWorkDetail {
+	detail, err := parseORCIDWorkDetail([]byte(testORCIDWorkDetailJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := *detail
+	generated.PutCode = 3002
+	generated.CitationType = ""
+	generated.CitationValue = ""
+	generated.Title = "Silly String & {Other} 100% Theories_v2"
+	generated.Contributors = append(slices.Clone(generated.Contributors), ORCIDContributor{Name: "Editor Person", Role: "editor"})
+
+	return []ORCIDWorkDetail{
+		*detail,
+		generated,
+		{
+			ORCIDWork: ORCIDWork{
+				PutCode:         3003,
+				Title:           "The Psychoceramics Handbook",
+				Type:            "book-chapter",
+				Journal:         "Handbook of Psychoceramics",
+				PublicationDate: &ORCIDDate{Year: 1995},
+			},
+			Contributors: []ORCIDContributor{{Name: "Josiah Carberry", Sequence: "first"}},
+		},
+		{
+			ORCIDWork: ORCIDWork{
+				PutCode: 3004,
+				Title:   "Cracked Pots Dataset",
+				Type:    "data-set",
+			},
+		},
+	}
+}
+
+func TestORCIDWorksToBibTeX(t *testing.T) {
+	works := testORCIDCitationWorks(t)
+
+	bibtex := ORCIDWorksToBibTeX(works)
+
+	testORCIDGolden(t, "orcid_works.bib", bibtex)
+
+	if again := ORCIDWorksToBibTeX(works); again != bibtex {
+		t.Fatalf("Expected deterministic output, got\n%s\nand\n%s", bibtex, again)
+	}
+
+	if v := ORCIDWorksToBibTeX(nil); v != "" {
+		t.Fatalf("Expected empty output for no works, got %q", v)
+	}
+}
+
+func TestEscapeBibTeX(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"plain text", "plain text"},
+		{`a & b % c $ d # e _ f`, `a \& b \% c \$ d \# e \_ f`},
+		{`{x} ~ ^ \`, `\{x\} \textasciitilde{} \textasciicircum{} \textbackslash{}`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := escapeBibTeX(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestORCIDWorksToCSLJSON(t *testing.T) {
+	works := testORCIDCitationWorks(t)
+
+	raw, err := ORCIDWorksToCSLJSON(works)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testORCIDGolden(t, "orcid_works.csl.json", string(raw))
+
+	if raw, _ := ORCIDWorksToCSLJSON(nil); string(raw) != "[]" {
+		t.Fatalf("Expected empty array for no works, got %s", raw)
+	}
+
+	if strings.Contains(string(raw), "null") {
+		t.Fatalf("Expected the missing fields to be omitted, got\n%s", raw)
+	}
+}
+
+func TestORCIDWorksToRIS(t *testing.T) {
+	works := testORCIDCitationWorks(t)
+
+	ris := ORCIDWorksToRIS(works)
+
+	testORCIDGolden(t, "orcid_works.ris", ris)
+
+	if again := ORCIDWorksToRIS(works); again != ris {
+		t.Fatalf("Expected deterministic output, got\n%s\nand\n%s", ris, again)
+	}
+
+	if v := ORCIDWorksToRIS(nil); v != "" {
+		t.Fatalf("Expected empty output for no works, got %q", v)
+	}
+
+	verbatim := ORCIDWorksToRIS([]ORCIDWorkDetail{{CitationType: "RIS", CitationValue: "\nTY  - JOUR\nER  - \n\n"}})
+	if verbatim != "TY  - JOUR\nER  - \n" {
+		t.Fatalf("Expected the ris citation to be exported verbatim, got %q", verbatim)
+	}
+}
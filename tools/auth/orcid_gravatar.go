@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// orcidGravatarBaseURL is the Gravatar avatar images base url.
+const orcidGravatarBaseURL = "https://www.gravatar.com/avatar/"
+
+// orcidGravatarURL returns the Gravatar image url of the specified email,
+// aka. the MD5 hash of the trimmed lowercased email.
+//
+// If email is empty, it returns the defaultImage url (or empty string
+// if defaultImage is not set).
+//
+// See https://docs.gravatar.com/api/avatars/images/
+func orcidGravatarURL(email string, defaultImage string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if email == "" {
+		if defaultImage == "" {
+			return ""
+		}
+
+		// "f=y" forces the default image regardless of the hash
+		return orcidGravatarBaseURL + "?d=" + url.QueryEscape(defaultImage) + "&f=y"
+	}
+
+	hash := md5.Sum([]byte(email))
+
+	avatarURL := orcidGravatarBaseURL + hex.EncodeToString(hash[:])
+	if defaultImage != "" {
+		avatarURL += "?d=" + url.QueryEscape(defaultImage)
+	}
+
+	return avatarURL
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestORCIDGravatarURL(t *testing.T) {
+	scenarios := []struct {
+		email        string
+		defaultImage string
+		expected     string
+	}{
+		{"", "", ""},
+		{"  ", "", ""},
+		{"", "mp", "https://www.gravatar.com/avatar/?d=mp&f=y"},
+		{"", "https://example.com/a b.png", "https://www.gravatar.com/avatar/?d=https%3A%2F%2Fexample.com%2Fa+b.png&f=y"},
+		{" MyEmailAddress@example.com ", "", "https://www.gravatar.com/avatar/0bc83cb571cd1c50ba6f3e8a78ef1346"},
+		{"myemailaddress@example.com", "identicon", "https://www.gravatar.com/avatar/0bc83cb571cd1c50ba6f3e8a78ef1346?d=identicon"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.email+"_"+s.defaultImage, func(t *testing.T) {
+			if v := orcidGravatarURL(s.email, s.defaultImage); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserGravatar(t *testing.T) {
+	scenarios := []struct {
+		name         string
+		person       string
+		opts         []ORCIDOption
+		expectedURL  string
+		expectedMail string
+	}{
+		{
+			"disabled",
+			testORCIDPersonJSON,
+			nil,
+			"",
+			"josiah@example.com",
+		},
+		{
+			"verified email",
+			testORCIDPersonJSON,
+			[]ORCIDOption{WithORCIDGravatar("")},
+			"https://www.gravatar.com/avatar/7aeb4ac1d57e424c50aa67621fe544d8",
+			"josiah@example.com",
+		},
+		{
+			"unverified email without default image",
+			`{"emails":{"email":[{"email":"josiah@example.com","verified":false}]}}`,
+			[]ORCIDOption{WithORCIDGravatar("")},
+			"",
+			"josiah@example.com",
+		},
+		{
+			"unverified email with default image",
+			`{"emails":{"email":[{"email":"josiah@example.com","verified":false}]}}`,
+			[]ORCIDOption{WithORCIDGravatar("identicon")},
+			"https://www.gravatar.com/avatar/?d=identicon&f=y",
+			"josiah@example.com",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(s.person))
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider(s.opts...)
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if user.Email != s.expectedMail {
+				t.Fatalf("Expected email %q, got %q", s.expectedMail, user.Email)
+			}
+
+			if user.AvatarURL != s.expectedURL {
+				t.Fatalf("Expected AvatarURL %q, got %q", s.expectedURL, user.AvatarURL)
+			}
+		})
+	}
+}
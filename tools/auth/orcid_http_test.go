@@ -0,0 +1,1244 @@
+package auth
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestORCIDBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	if d := orcidBackoff(0, 3); d != 0 {
+		t.Fatalf("Expected 0 delay for 0 base delay, got %v", d)
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		min := base << attempt
+		max := min + base
+
+		for i := 0; i < 10; i++ {
+			d := orcidBackoff(base, attempt)
+			if d < min || d >= max {
+				t.Fatalf("[%d] Expected delay in [%v, %v), got %v", attempt, min, max, d)
+			}
+		}
+	}
+}
+
+func TestORCIDFetchRetry(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		maxRetries       int
+		failures         int32
+		failureStatus    int
+		expectError      bool
+		expectedRequests int32
+	}{
+		{"no failures", 2, 0, 0, false, 1},
+		{"5xx failures below the limit", 2, 2, http.StatusBadGateway, false, 3},
+		{"5xx failures above the limit", 2, 3, http.StatusServiceUnavailable, true, 3},
+		{"5xx failure with disabled retries", 0, 1, http.StatusInternalServerError, true, 1},
+		{"4xx failure", 2, 1, http.StatusNotFound, true, 1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var totalRequests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if totalRequests.Add(1) <= s.failures {
+					w.WriteHeader(s.failureStatus)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+			p.MaxRetries = s.maxRetries
+			p.RetryBaseDelay = time.Millisecond
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			_, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if total := totalRequests.Load(); total != s.expectedRequests {
+				t.Fatalf("Expected %d requests, got %d", s.expectedRequests, total)
+			}
+
+			var apiErr *ORCIDAPIError
+			if hasErr && (!errors.As(err, &apiErr) || apiErr.Status != s.failureStatus) {
+				t.Fatalf("Expected ORCIDAPIError with status %d, got %v", s.failureStatus, err)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchRetryNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // close immediately to simulate connection errors
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.MaxRetries = 2
+	p.RetryBaseDelay = time.Millisecond
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	var retries int
+	err := p.retry(context.Background(), func() error {
+		retries++
+		_, err := p.sendRequest(context.Background(), token, p.apiURL("0000-0002-1825-0097", "/person"))
+		return err
+	})
+	if err == nil {
+		t.Fatal("Expected network error, got nil")
+	}
+
+	if retries != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", retries)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"invalid", 0},
+		{"-1", 0},
+		{"0", 0},
+		{"5", 5 * time.Second},
+		{time.Now().Add(-1 * time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := parseRetryAfter(s.value); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+
+	t.Run("future http date", func(t *testing.T) {
+		v := parseRetryAfter(time.Now().Add(1 * time.Minute).UTC().Format(http.TimeFormat))
+		if v <= 50*time.Second || v > 1*time.Minute {
+			t.Fatalf("Expected ~1m delay, got %v", v)
+		}
+	})
+}
+
+func TestORCIDFetchRateLimited(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		retryAfter       string
+		maxRetryAfter    time.Duration
+		expectError      bool
+		expectedRequests int32
+	}{
+		{"retry after within the limit", "1", 2 * time.Second, false, 2},
+		{"retry after above the limit", "120", 2 * time.Second, true, 1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var totalRequests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if totalRequests.Add(1) == 1 {
+					w.Header().Set("Retry-After", s.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+			p.MaxRetryAfter = s.maxRetryAfter
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if total := totalRequests.Load(); total != s.expectedRequests {
+				t.Fatalf("Expected %d requests, got %d", s.expectedRequests, total)
+			}
+
+			if hasErr {
+				if !errors.Is(err, ErrORCIDRateLimited) {
+					t.Fatalf("Expected ErrORCIDRateLimited, got %v", err)
+				}
+
+				var rateLimitErr *ORCIDRateLimitError
+				if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 120*time.Second {
+					t.Fatalf("Expected ORCIDRateLimitError with 120s RetryAfter, got %v", err)
+				}
+
+				var apiErr *ORCIDAPIError
+				if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests {
+					t.Fatalf("Expected ORCIDAPIError with 429 status, got %v", err)
+				}
+			} else if user.Name != "Josiah" {
+				t.Fatalf("Expected name %q, got %q", "Josiah", user.Name)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchRateLimitedWithoutRetryAfter(t *testing.T) {
+	var mu sync.Mutex
+	var requestTimes []time.Time
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes = append(requestTimes, time.Now())
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	const baseDelay = 50 * time.Millisecond
+
+	p := NewORCIDProvider(WithORCIDRetries(2, baseDelay))
+	p.APIBaseURL = srv.URL
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	_, err := p.FetchAuthUser(token)
+	if !errors.Is(err, ErrORCIDRateLimited) {
+		t.Fatalf("Expected ErrORCIDRateLimited, got %v", err)
+	}
+
+	if len(requestTimes) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requestTimes))
+	}
+
+	// exponential backoff - (base << attempt) + jitter
+	for i := 1; i < len(requestTimes); i++ {
+		minDelay := baseDelay << (i - 1)
+		if d := requestTimes[i].Sub(requestTimes[i-1]); d < minDelay {
+			t.Fatalf("Expected request %d to be delayed with at least %v, got %v", i+1, minDelay, d)
+		}
+	}
+}
+
+func TestORCIDFetchTimeout(t *testing.T) {
+	var totalRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests.Add(1)
+
+		// simulate a hung connection
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.Timeout = 100 * time.Millisecond
+	p.RetryBaseDelay = 1 * time.Millisecond
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	start := time.Now()
+
+	_, err := p.FetchAuthUser(token)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded error, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected the fetch to timeout in ~100ms, took %v", elapsed)
+	}
+
+	if total := totalRequests.Load(); total != 1 {
+		t.Fatalf("Expected the timed out request to not be retried, got %d requests", total)
+	}
+}
+
+type testORCIDRoundTripper struct {
+	total atomic.Int32
+}
+
+func (rt *testORCIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.total.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestORCIDProxy(t *testing.T) {
+	var proxiedHosts []string
+	var mu sync.Mutex
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxiedHosts = append(proxiedHosts, r.URL.Host)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/oauth/token" {
+			fmt.Fprint(w, `{"access_token":"test_access_token","token_type":"bearer","orcid":"0000-0002-1825-0097"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+	}))
+	defer proxy.Close()
+
+	customClient := &http.Client{Timeout: 7 * time.Second}
+
+	// the .invalid hosts are not resolvable and reachable only through the proxy
+	p := NewORCIDProvider(
+		WithORCIDHTTPClient(customClient),
+		WithORCIDProxy(proxy.URL),
+		WithORCIDAPIBaseURL("http://api.orcid.invalid"),
+	)
+	p.SetTokenURL("http://orcid.invalid/oauth/token")
+
+	if p.HTTPClient().Timeout != customClient.Timeout {
+		t.Fatalf("Expected the custom client timeout %v to be preserved, got %v", customClient.Timeout, p.HTTPClient().Timeout)
+	}
+
+	token, err := p.FetchToken("test_code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Name != "Josiah" {
+		t.Fatalf("Expected name %q, got %q", "Josiah", user.Name)
+	}
+
+	expectedHosts := []string{"orcid.invalid", "api.orcid.invalid"}
+	if !slices.Equal(proxiedHosts, expectedHosts) {
+		t.Fatalf("Expected proxied hosts %v, got %v", expectedHosts, proxiedHosts)
+	}
+
+	t.Run("invalid proxy url", func(t *testing.T) {
+		p := NewORCIDProvider(
+			WithORCIDProxy("proxy.internal:3128"),
+			WithORCIDAPIBaseURL("http://api.orcid.invalid"),
+			WithORCIDRetries(0, 0),
+		)
+
+		token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+		_, err := p.FetchAuthUser(token)
+		if err == nil || !strings.Contains(err.Error(), "invalid ORCID proxy url") {
+			t.Fatalf("Expected invalid proxy url error, got %v", err)
+		}
+	})
+}
+
+func TestORCIDProxyCustomTransport(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "custom.orcid.invalid", MinVersion: tls.VersionTLS13}
+
+	original := &http.Transport{
+		TLSClientConfig:       tlsConfig,
+		ResponseHeaderTimeout: 3 * time.Second,
+	}
+
+	p := NewORCIDProvider(
+		WithORCIDHTTPClient(&http.Client{Transport: original}),
+		WithORCIDProxy("http://proxy.internal:3128"),
+	)
+
+	transport, ok := p.HTTPClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", p.HTTPClient().Transport)
+	}
+
+	if transport == original {
+		t.Fatal("Expected a copy of the custom transport")
+	}
+
+	if original.Proxy != nil {
+		t.Fatal("Expected the custom transport to not be modified")
+	}
+
+	if transport.TLSClientConfig == nil ||
+		transport.TLSClientConfig.ServerName != tlsConfig.ServerName ||
+		transport.TLSClientConfig.MinVersion != tlsConfig.MinVersion {
+		t.Fatalf("Expected the custom TLS config to be preserved, got %#v", transport.TLSClientConfig)
+	}
+
+	if transport.ResponseHeaderTimeout != original.ResponseHeaderTimeout {
+		t.Fatalf("Expected ResponseHeaderTimeout %v, got %v", original.ResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	}
+
+	req, _ := http.NewRequest("GET", "https://pub.orcid.org", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.internal:3128" {
+		t.Fatalf("Expected proxy.internal:3128 proxy, got %v (%v)", proxyURL, err)
+	}
+}
+
+func TestORCIDFetchTokenTimeout(t *testing.T) {
+	var totalRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests.Add(1)
+
+		// consume the body so that the client disconnect could be detected
+		r.ParseForm()
+
+		// simulate a slow token endpoint
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(3 * time.Second):
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"test_access_token","token_type":"bearer","orcid":"0000-0002-1825-0097"}`)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.SetTokenURL(srv.URL + "/oauth/token")
+	p.Timeout = 200 * time.Millisecond
+	p.RetryBaseDelay = 1 * time.Millisecond
+
+	start := time.Now()
+
+	token, err := p.FetchToken("test_code")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded error, got %v (token %v)", err, token)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected the exchange to timeout in ~200ms, took %v", elapsed)
+	}
+
+	if total := totalRequests.Load(); total != 1 {
+		t.Fatalf("Expected the single-use code exchange to not be retried, got %d requests", total)
+	}
+}
+
+func TestORCIDSetClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/oauth/token" {
+			fmt.Fprint(w, `{"access_token":"test_access_token","token_type":"bearer","orcid":"0000-0002-1825-0097"}`)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer test_access_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}}}`)
+	}))
+	defer srv.Close()
+
+	transport := &testORCIDRoundTripper{}
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.SetTokenURL(srv.URL + "/oauth/token")
+
+	if p.HTTPClient() != http.DefaultClient {
+		t.Fatal("Expected the default HTTP client")
+	}
+
+	p.SetClient(&http.Client{Transport: transport})
+
+	token, err := p.FetchToken("test_code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Name != "Josiah" {
+		t.Fatalf("Expected name %q, got %q", "Josiah", user.Name)
+	}
+
+	if total := transport.total.Load(); total != 2 {
+		t.Fatalf("Expected 2 requests through the custom client, got %d", total)
+	}
+}
+
+func TestORCIDAPIErrorBody(t *testing.T) {
+	scenarios := []struct {
+		name                     string
+		body                     string
+		expectedErrorCode        int
+		expectedDeveloperMessage string
+		expectedUserMessage      string
+		expectedMoreInfo         string
+	}{
+		{
+			"ORCID JSON error body",
+			`{
+				"response-code": 404,
+				"developer-message": "404 Not Found: The resource was not found. Full validation error: 0000-0002-1825-0098 is not a valid ORCID iD",
+				"user-message": "The resource was not found.",
+				"error-code": 9016,
+				"more-info": "https://members.orcid.org/api/resources/troubleshooting"
+			}`,
+			9016,
+			"404 Not Found: The resource was not found. Full validation error: 0000-0002-1825-0098 is not a valid ORCID iD",
+			"The resource was not found.",
+			"https://members.orcid.org/api/resources/troubleshooting",
+		},
+		{
+			"non JSON error body",
+			`<html><body>Bad Gateway</body></html>`,
+			0, "", "", "",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, s.body)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			_, err := p.FetchAuthUser(token)
+
+			var apiErr *ORCIDAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected ORCIDAPIError, got %v", err)
+			}
+
+			if apiErr.Status != http.StatusNotFound {
+				t.Fatalf("Expected status %d, got %d", http.StatusNotFound, apiErr.Status)
+			}
+
+			if apiErr.Body != s.body {
+				t.Fatalf("Expected body %q, got %q", s.body, apiErr.Body)
+			}
+
+			if apiErr.ErrorCode != s.expectedErrorCode {
+				t.Fatalf("Expected error code %d, got %d", s.expectedErrorCode, apiErr.ErrorCode)
+			}
+
+			if apiErr.DeveloperMessage != s.expectedDeveloperMessage {
+				t.Fatalf("Expected developer message %q, got %q", s.expectedDeveloperMessage, apiErr.DeveloperMessage)
+			}
+
+			if apiErr.UserMessage != s.expectedUserMessage {
+				t.Fatalf("Expected user message %q, got %q", s.expectedUserMessage, apiErr.UserMessage)
+			}
+
+			if apiErr.MoreInfo != s.expectedMoreInfo {
+				t.Fatalf("Expected more info %q, got %q", s.expectedMoreInfo, apiErr.MoreInfo)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserErrorStatus(t *testing.T) {
+	scenarios := []struct {
+		status        int
+		body          string
+		expectedHints []string
+	}{
+		{
+			http.StatusForbidden,
+			`{"response-code":403,"developer-message":"403 Forbidden: You don't have the right permissions to access this resource.","user-message":"Access denied","error-code":9017}`,
+			[]string{"403", "error code 9017", "right permissions", "hint:", "scope"},
+		},
+		{
+			http.StatusNotFound,
+			`{"response-code":404,"developer-message":"404 Not Found: The resource was not found.","user-message":"The resource was not found.","error-code":9016}`,
+			[]string{"404", "error code 9016", "hint:", "doesn't exist"},
+		},
+		{
+			http.StatusBadRequest,
+			`invalid request`,
+			[]string{"400", "invalid request"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprint(s.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(s.status)
+				fmt.Fprint(w, s.body)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			_, err := p.FetchAuthUser(token)
+
+			var apiErr *ORCIDAPIError
+			if !errors.As(err, &apiErr) || apiErr.Status != s.status {
+				t.Fatalf("Expected ORCIDAPIError with status %d, got %v", s.status, err)
+			}
+
+			for _, hint := range s.expectedHints {
+				if !strings.Contains(err.Error(), hint) {
+					t.Fatalf("Expected %q in the error message, got %q", hint, err.Error())
+				}
+			}
+
+			if s.status == http.StatusBadRequest && strings.Contains(err.Error(), "hint:") {
+				t.Fatalf("Expected no hint, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserInactiveRecord(t *testing.T) {
+	scenarios := []struct {
+		name              string
+		body              string
+		expectedError     error
+		expectedPrimaryId string
+	}{
+		{
+			"deactivated",
+			`{"response-code":409,"developer-message":"409 Conflict: The ORCID record is deactivated.","user-message":"The ORCID record is deactivated.","error-code":9044}`,
+			ErrDeactivatedORCID,
+			"",
+		},
+		{
+			"deprecated with primary record in the message",
+			`{"response-code":409,"developer-message":"409 Conflict: The ORCID record 0000-0002-1825-0097 is deprecated and the primary record is 0000-0001-5109-3700.","user-message":"The ORCID record is deprecated.","error-code":9007}`,
+			ErrDeprecatedORCID,
+			"0000-0001-5109-3700",
+		},
+		{
+			"deprecated with primary-record field",
+			`{"response-code":409,"developer-message":"409 Conflict: Deprecated record.","error-code":9007,"primary-record":{"orcid-identifier":{"uri":"https://orcid.org/0000-0002-1694-233X","path":"0000-0002-1694-233X"}}}`,
+			ErrDeprecatedORCID,
+			"0000-0002-1694-233X",
+		},
+		{
+			"deprecated without known primary record",
+			`{"response-code":409,"developer-message":"409 Conflict: The ORCID record is deprecated.","error-code":9007}`,
+			ErrDeprecatedORCID,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			srv.setResponse("/v3.0/"+testORCIDServeriD+"/person", http.StatusConflict, s.body)
+
+			_, err := srv.provider().FetchAuthUser(srv.token())
+
+			if !errors.Is(err, s.expectedError) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, err)
+			}
+
+			var statusErr *ORCIDRecordStatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("Expected ORCIDRecordStatusError, got %T", err)
+			}
+
+			if statusErr.PrimaryId != s.expectedPrimaryId {
+				t.Fatalf("Expected primary iD %q, got %q", s.expectedPrimaryId, statusErr.PrimaryId)
+			}
+
+			var apiErr *ORCIDAPIError
+			if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+				t.Fatalf("Expected ORCIDAPIError with status 409, got %v", err)
+			}
+		})
+	}
+
+	t.Run("other conflict", func(t *testing.T) {
+		srv := newTestORCIDServer(t)
+		srv.setResponse("/v3.0/"+testORCIDServeriD+"/person", http.StatusConflict, `{"response-code":409,"developer-message":"409 Conflict: other","error-code":9000}`)
+
+		_, err := srv.provider().FetchAuthUser(srv.token())
+
+		var statusErr *ORCIDRecordStatusError
+		if errors.As(err, &statusErr) {
+			t.Fatalf("Expected a plain ORCIDAPIError, got %v", err)
+		}
+	})
+}
+
+func TestORCIDFetchAuthUserMovedRecord(t *testing.T) {
+	const primaryiD = "0000-0001-5109-3700"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3.0/" + testORCIDServeriD + "/person":
+			http.Redirect(w, r, "/v3.0/"+primaryiD+"/person", http.StatusMovedPermanently)
+		case "/v3.0/" + primaryiD + "/person":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(testORCIDPersonJSON))
+		case "/v3.0/" + testORCIDServeriD + "/works":
+			// same iD redirects are followed as usual
+			http.Redirect(w, r, "/v3.0/"+testORCIDServeriD+"/works/", http.StatusFound)
+		case "/v3.0/" + testORCIDServeriD + "/works/":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"group":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": testORCIDServeriD})
+
+	t.Run("without FollowPrimaryRecord", func(t *testing.T) {
+		p := NewORCIDProvider()
+		p.APIBaseURL = srv.URL
+
+		_, err := p.FetchAuthUser(token)
+		if !errors.Is(err, ErrDeprecatedORCID) {
+			t.Fatalf("Expected ErrDeprecatedORCID, got %v", err)
+		}
+
+		var statusErr *ORCIDRecordStatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("Expected ORCIDRecordStatusError, got %T", err)
+		}
+
+		if statusErr.PrimaryId != primaryiD {
+			t.Fatalf("Expected primary iD %q, got %q", primaryiD, statusErr.PrimaryId)
+		}
+
+		if expected := srv.URL + "/v3.0/" + primaryiD + "/person"; statusErr.Location != expected {
+			t.Fatalf("Expected Location %q, got %q", expected, statusErr.Location)
+		}
+
+		if statusErr.Status != http.StatusMovedPermanently {
+			t.Fatalf("Expected status 301, got %d", statusErr.Status)
+		}
+	})
+
+	t.Run("with FollowPrimaryRecord", func(t *testing.T) {
+		p := NewORCIDProvider(WithORCIDFollowPrimaryRecord())
+		p.APIBaseURL = srv.URL
+
+		user, err := p.FetchAuthUser(token)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+
+		if user.Id != primaryiD || user.Username != primaryiD {
+			t.Fatalf("Expected Id and Username %q, got %q and %q", primaryiD, user.Id, user.Username)
+		}
+
+		if v := user.RawUser["deprecated_orcid"]; v != testORCIDServeriD {
+			t.Fatalf("Expected deprecated_orcid %q, got %v", testORCIDServeriD, v)
+		}
+	})
+
+	t.Run("same iD redirect", func(t *testing.T) {
+		p := NewORCIDProvider()
+		p.APIBaseURL = srv.URL
+
+		if _, err := p.FetchWorks(token); err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+	})
+}
+
+func TestORCIDFetchAuthUserFollowPrimaryRecord(t *testing.T) {
+	srv := newTestORCIDServer(t)
+	srv.setResponse("/v3.0/"+testORCIDServeriD+"/person", http.StatusConflict, `{"response-code":409,"developer-message":"409 Conflict: Deprecated record.","error-code":9007,"primary-record":{"orcid-identifier":{"path":"0000-0001-5109-3700"}}}`)
+	srv.setResponse("/v3.0/0000-0001-5109-3700/person", http.StatusOK, testORCIDPersonJSON)
+
+	user, err := srv.provider(WithORCIDFollowPrimaryRecord()).FetchAuthUser(srv.token())
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if user.Id != "0000-0001-5109-3700" {
+		t.Fatalf("Expected the primary iD, got %q", user.Id)
+	}
+}
+
+func TestORCIDFetchUserAgent(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		userAgent *string
+		expected  string
+	}{
+		{"default", nil, ORCIDDefaultUserAgent},
+		{"custom", func() *string { v := "MyApp/1.0 (+https://example.com)"; return &v }(), "MyApp/1.0 (+https://example.com)"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var userAgent string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.Header.Get("User-Agent")
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{}`)
+			}))
+			defer srv.Close()
+
+			var opts []ORCIDOption
+			if s.userAgent != nil {
+				opts = append(opts, WithORCIDUserAgent(*s.userAgent))
+			}
+
+			p := NewORCIDProvider(opts...)
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			if _, err := p.FetchAuthUser(token); err != nil {
+				t.Fatal(err)
+			}
+
+			if userAgent != s.expected {
+				t.Fatalf("Expected User-Agent %q, got %q", s.expected, userAgent)
+			}
+		})
+	}
+}
+
+// testRawFetcher is a rawFetcher that serves fixtures keyed by url.
+type testRawFetcher struct {
+	responses map[string]string
+	urls      []string
+}
+
+func (f *testRawFetcher) fetchRaw(ctx context.Context, token *oauth2.Token, url string) ([]byte, error) {
+	f.urls = append(f.urls, url)
+
+	body, ok := f.responses[url]
+	if !ok {
+		return nil, &ORCIDAPIError{URL: url, Status: http.StatusNotFound}
+	}
+
+	return []byte(body), nil
+}
+
+func TestORCIDRawFetcher(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		strictEmail   bool
+		person        string
+		expectedName  string
+		expectedEmail string
+	}{
+		{
+			"primary verified email",
+			false,
+			testORCIDPersonJSON,
+			"Josiah S. Carberry",
+			"josiah@example.com",
+		},
+		{
+			"unverified email",
+			false,
+			`{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[{"email":"unverified@example.com","verified":false}]}}`,
+			"Josiah",
+			"unverified@example.com",
+		},
+		{
+			"unverified email with StrictEmail",
+			true,
+			`{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[{"email":"unverified@example.com","verified":false}]}}`,
+			"Josiah",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewORCIDProvider()
+			p.StrictEmail = s.strictEmail
+
+			personURL := p.apiURL("0000-0002-1825-0097", "/person")
+
+			fetcher := &testRawFetcher{responses: map[string]string{personURL: s.person}}
+			p.fetcher = fetcher
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Name != s.expectedName {
+				t.Fatalf("Expected name %q, got %q", s.expectedName, user.Name)
+			}
+
+			if user.Email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, user.Email)
+			}
+
+			if len(fetcher.urls) != 1 || fetcher.urls[0] != personURL {
+				t.Fatalf("Expected a single %s fetch, got %v", personURL, fetcher.urls)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserContextCancel(t *testing.T) {
+	started := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done() // block until the client aborts the request
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+	p.Timeout = 0
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.FetchAuthUserContext(ctx, token)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancelled request to be aborted")
+	}
+}
+
+func TestORCIDFetchContextCanceledBeforeRequest(t *testing.T) {
+	srv := newTestORCIDServer(t)
+
+	p := srv.provider()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checks := map[string]func() error{
+		"FetchWorksContext": func() error {
+			_, err := p.FetchWorksContext(ctx, srv.token())
+			return err
+		},
+		"FetchEmailContext": func() error {
+			_, _, err := p.FetchEmailContext(ctx, srv.token())
+			return err
+		},
+		"FetchTokenContext": func() error {
+			_, err := p.FetchTokenContext(ctx, "test_code")
+			return err
+		},
+	}
+
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			if err := check(); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected context.Canceled error, got %v", err)
+			}
+		})
+	}
+
+	if paths := srv.requestedPaths(); len(paths) != 0 {
+		t.Fatalf("Expected no requests, got %v", paths)
+	}
+
+	// the non-context methods still use the provider context
+	if _, err := p.FetchWorks(srv.token()); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
+func TestORCIDFetchMaxBodySize(t *testing.T) {
+	body := `{"group":[]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	if p := NewORCIDProvider(); p.MaxBodySize != ORCIDDefaultMaxBodySize {
+		t.Fatalf("Expected default MaxBodySize %d, got %d", ORCIDDefaultMaxBodySize, p.MaxBodySize)
+	}
+
+	scenarios := []struct {
+		name        string
+		maxBodySize int64
+		expectError bool
+	}{
+		{"no limit", 0, false},
+		{"limit equal to the body size", int64(len(body)), false},
+		{"limit smaller than the body size", int64(len(body)) - 1, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewORCIDProvider(WithORCIDMaxBodySize(s.maxBodySize))
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			_, err := p.FetchWorks(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if s.expectError && !errors.Is(err, ErrORCIDResponseTooLarge) {
+				t.Fatalf("Expected ErrORCIDResponseTooLarge, got %v", err)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserSingleflight(t *testing.T) {
+	var personRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		personRequests.Add(1)
+
+		// keep the request in-flight long enough for all callers to join it
+		time.Sleep(200 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testORCIDPersonJSON))
+	}))
+	defer srv.Close()
+
+	const total = 10
+
+	errs := make(chan error, total)
+
+	for i := 0; i < total; i++ {
+		go func() {
+			// new provider instance per call similar to PocketBase
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+
+			// the same session token
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err == nil && user.Name != "Josiah S. Carberry" {
+				err = fmt.Errorf("unexpected user name %q", user.Name)
+			}
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < total; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+	}
+
+	if n := personRequests.Load(); n != 1 {
+		t.Fatalf("Expected 1 upstream request, got %d", n)
+	}
+}
+
+func TestORCIDFetchAuthUserSingleflightDifferentTokens(t *testing.T) {
+	var personRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		personRequests.Add(1)
+
+		// keep the request in-flight long enough for the other caller to (not) join it
+		time.Sleep(200 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Header.Get("Authorization") {
+		case "Bearer member":
+			w.Write([]byte(`{"name":{"given-names":{"value":"Limited"}}}`))
+		case "Bearer public":
+			w.Write([]byte(`{"name":{"given-names":{"value":"Public"}}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	scenarios := []struct {
+		accessToken  string
+		expectedName string
+		expectError  bool
+	}{
+		{"member", "Limited", false},
+		{"public", "Public", false},
+		{"expired", "", true},
+	}
+
+	var wg sync.WaitGroup
+
+	results := make([]string, len(scenarios))
+	errs := make([]error, len(scenarios))
+
+	for i, s := range scenarios {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			p := NewORCIDProvider(WithORCIDRetries(0, 0))
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: s.accessToken}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err == nil {
+				results[i] = user.Name
+			}
+			errs[i] = err
+		}()
+	}
+
+	wg.Wait()
+
+	for i, s := range scenarios {
+		if hasErr := errs[i] != nil; hasErr != s.expectError {
+			t.Fatalf("[%s] Expected hasErr %v, got %v (%v)", s.accessToken, s.expectError, hasErr, errs[i])
+		}
+
+		if results[i] != s.expectedName {
+			t.Fatalf("[%s] Expected name %q, got %q", s.accessToken, s.expectedName, results[i])
+		}
+	}
+
+	if n := personRequests.Load(); n != int32(len(scenarios)) {
+		t.Fatalf("Expected %d upstream requests, got %d", len(scenarios), n)
+	}
+}
+
+func TestORCIDFetchGzipResponse(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(testORCIDPersonJSON))
+	gz.Close()
+
+	var acceptEncoding string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer srv.Close()
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	scenarios := []struct {
+		name        string
+		maxBodySize int64
+		expectError bool
+	}{
+		{"no limit", 0, false},
+		{"decompressed body within the limit", int64(len(testORCIDPersonJSON)), false},
+		// the compressed body is within the limit but the decompressed is not
+		{"decompressed body above the limit", int64(compressed.Len()), true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			FlushORCIDPersonCache()
+
+			p := NewORCIDProvider(WithORCIDMaxBodySize(s.maxBodySize))
+			p.APIBaseURL = srv.URL
+
+			user, err := p.FetchAuthUser(token)
+
+			if acceptEncoding != "gzip" {
+				t.Fatalf("Expected Accept-Encoding gzip, got %q", acceptEncoding)
+			}
+
+			if s.expectError {
+				if !errors.Is(err, ErrORCIDResponseTooLarge) {
+					t.Fatalf("Expected ErrORCIDResponseTooLarge, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if user.Name != "Josiah S. Carberry" {
+				t.Fatalf("Expected the decompressed person name, got %q", user.Name)
+			}
+		})
+	}
+
+	t.Run("invalid gzip body", func(t *testing.T) {
+		invalidSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write([]byte(testORCIDPersonJSON))
+		}))
+		defer invalidSrv.Close()
+
+		p := NewORCIDProvider()
+		p.APIBaseURL = invalidSrv.URL
+
+		if _, err := p.FetchAuthUser(token); err == nil || !strings.Contains(err.Error(), "decompress") {
+			t.Fatalf("Expected decompress error, got %v", err)
+		}
+	})
+}
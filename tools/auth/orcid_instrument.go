@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// orcidRedacted is the placeholder of the redacted log values.
+const orcidRedacted = "REDACTED"
+
+// orcidSensitiveParams are the case-insensitive names of the url query
+// params and headers that are never logged.
+var orcidSensitiveParams = []string{
+	"authorization",
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"token",
+	"client_secret",
+	"code",
+	"code_verifier",
+}
+
+// transportClient returns the provider HTTP client wrapped with the
+// logging and metrics instrumentation (if enabled).
+//
+// It returns nil if there is neither custom client nor instrumentation
+// so that the callers could fallback to their defaults.
+func (p *ORCID) transportClient() *http.Client {
+	if p.Logger == nil && p.Metrics == nil {
+		return p.httpClient
+	}
+
+	client := http.Client{}
+	if p.httpClient != nil {
+		client = *p.httpClient
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	client.Transport = &orcidInstrumentedTransport{base: base, p: p}
+
+	return &client
+}
+
+// orcidInstrumentedTransport is a [http.RoundTripper] that reports
+// the ORCID requests to the provider Logger and Metrics.
+type orcidInstrumentedTransport struct {
+	base http.RoundTripper
+	p    *ORCID
+}
+
+// RoundTrip implements [http.RoundTripper.RoundTrip].
+func (t *orcidInstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(req)
+
+	duration := time.Since(start)
+
+	t.p.logRequest(req, res, err, duration)
+	t.p.observeRequest(req, res, err, duration)
+
+	return res, err
+}
+
+// logRequest logs a single ORCID request with redacted credentials.
+//
+// Successful requests are logged with debug level and the failed ones
+// (network errors and 4xx/5xx responses) with warn level.
+func (p *ORCID) logRequest(req *http.Request, res *http.Response, err error, duration time.Duration) {
+	if p.Logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+
+	attrs := []slog.Attr{
+		slog.String("provider", NameORCID),
+		slog.String("method", req.Method),
+		slog.String("url", redactORCIDURL(req.URL)),
+		slog.Duration("duration", duration),
+		slog.Any("headers", redactORCIDHeaders(req.Header)),
+	}
+
+	if res != nil {
+		attrs = append(attrs, slog.Int("status", res.StatusCode))
+		if res.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level = slog.LevelWarn
+	}
+
+	ctx := req.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p.Logger.LogAttrs(ctx, level, "ORCID request", attrs...)
+}
+
+func isORCIDSensitiveParam(name string) bool {
+	for _, sensitive := range orcidSensitiveParams {
+		if strings.EqualFold(name, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactORCIDURL returns the string representation of u
+// with redacted sensitive query params and user info.
+func redactORCIDURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	clone := *u
+
+	if clone.User != nil {
+		clone.User = url.User(orcidRedacted)
+	}
+
+	if clone.RawQuery != "" {
+		query := clone.Query()
+		for name := range query {
+			if isORCIDSensitiveParam(name) {
+				query.Set(name, orcidRedacted)
+			}
+		}
+		clone.RawQuery = query.Encode()
+	}
+
+	return clone.String()
+}
+
+// redactORCIDHeaders returns a flattened copy of the headers with redacted sensitive values.
+func redactORCIDHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string, len(headers))
+
+	for name, values := range headers {
+		if isORCIDSensitiveParam(name) {
+			result[name] = orcidRedacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+
+	return result
+}
+
+// ORCID request metric outcomes.
+const (
+	ORCIDOutcomeSuccess     = "success"
+	ORCIDOutcomeRateLimited = "rate_limited"
+	ORCIDOutcomeError       = "error"
+)
+
+// ORCIDRequestMetric defines the metric labels and values of a single ORCID request.
+type ORCIDRequestMetric struct {
+	// Endpoint is the requested ORCID endpoint, eg. "person", "email",
+	// "works", "token", "jwks", "search" or "other".
+	Endpoint string
+
+	// Outcome is one of [ORCIDOutcomeSuccess], [ORCIDOutcomeRateLimited] or [ORCIDOutcomeError].
+	Outcome string
+
+	// StatusClass is the response status class ("2xx", "3xx", "4xx", "5xx")
+	// or "network" if there is no response.
+	StatusClass string
+
+	// Status is the response status code (0 if there is no response).
+	Status int
+
+	Duration time.Duration
+}
+
+// ORCIDMetrics defines a metrics hook that is called after each ORCID
+// API, token and jwks request (eg. to adapt it to Prometheus or OpenTelemetry).
+//
+// Implementations must be safe for concurrent use.
+type ORCIDMetrics interface {
+	ObserveORCIDRequest(metric ORCIDRequestMetric)
+}
+
+// observeRequest reports a single ORCID request to the provider Metrics (if set).
+func (p *ORCID) observeRequest(req *http.Request, res *http.Response, err error, duration time.Duration) {
+	if p.Metrics == nil {
+		return
+	}
+
+	metric := ORCIDRequestMetric{
+		Endpoint:    orcidEndpoint(req.URL),
+		Outcome:     ORCIDOutcomeError,
+		StatusClass: "network",
+		Duration:    duration,
+	}
+
+	if err == nil && res != nil {
+		metric.Status = res.StatusCode
+		metric.StatusClass = strconv.Itoa(res.StatusCode/100) + "xx"
+
+		switch {
+		case res.StatusCode == http.StatusTooManyRequests:
+			metric.Outcome = ORCIDOutcomeRateLimited
+		case res.StatusCode < 400:
+			metric.Outcome = ORCIDOutcomeSuccess
+		}
+	}
+
+	p.Metrics.ObserveORCIDRequest(metric)
+}
+
+// orcidEndpoint returns the metric endpoint label of the specified ORCID url.
+//
+// The API endpoints are labeled with their record section
+// (eg. "/v3.0/0000-0002-1825-0097/work/123" -> "work") to keep the labels cardinality low.
+func orcidEndpoint(u *url.URL) string {
+	if u == nil {
+		return "other"
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	for i, part := range parts {
+		if part == "oauth" && i+1 < len(parts) {
+			// eg. "token", "token/introspect", "revoke", "jwks"
+			return strings.Join(parts[i+1:], "/")
+		}
+
+		if validateORCIDiD(part) == nil {
+			if i+1 < len(parts) {
+				return parts[i+1]
+			}
+			return "record"
+		}
+
+		if part == "search" || part == "expanded-search" {
+			return part
+		}
+	}
+
+	return "other"
+}
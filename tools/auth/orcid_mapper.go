@@ -0,0 +1,50 @@
+package auth
+
+// MapORCIDToRecord maps the ORCID auth user returned by [ORCID.FetchAuthUser]
+// (and optionally the user employments returned by [ORCID.FetchEmployments])
+// into a field map ready to be loaded into a PocketBase auth record
+// (eg. with record.Load(fields) in an OnRecordAuthWithOAuth2Request hook).
+//
+// The map always contains the following keys (with empty string for the unknown values)
+// so that the previously stored values are cleared:
+//   - "orcid" - the bare ORCID iD (eg. "0000-0002-1825-0097")
+//   - "orcid_uri" - the canonical iD URI
+//   - "name", "given_names", "family_name" - the resolved and the public name components
+//   - "country" - the public ISO 3166 country code
+//   - "affiliation", "department", "role_title" - of the current employment (see [CurrentORCIDAffiliation])
+//
+// Unneeded keys could be deleted or renamed to match the collection fields.
+func MapORCIDToRecord(user *AuthUser, employments []ORCIDAffiliation) map[string]any {
+	fields := map[string]any{
+		"orcid":       "",
+		"orcid_uri":   "",
+		"name":        "",
+		"given_names": "",
+		"family_name": "",
+		"country":     "",
+		"affiliation": "",
+		"department":  "",
+		"role_title":  "",
+	}
+
+	if user == nil {
+		return fields
+	}
+
+	fields["orcid"] = user.Username
+	fields["name"] = user.Name
+
+	for _, key := range []string{"orcid_uri", "given_names", "family_name", "country"} {
+		if v, ok := user.RawUser[key].(string); ok {
+			fields[key] = v
+		}
+	}
+
+	if current := CurrentORCIDAffiliation(employments); current != nil {
+		fields["affiliation"] = current.Organization
+		fields["department"] = current.Department
+		fields["role_title"] = current.RoleTitle
+	}
+
+	return fields
+}
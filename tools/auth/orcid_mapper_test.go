@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapORCIDToRecord(t *testing.T) {
+	user := testORCIDFetchAuthUser(t, testORCIDPersonJSON)
+
+	employments, err := parseORCIDAffiliations([]byte(testORCIDEmploymentsJSON), "employment-summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty := map[string]any{
+		"orcid":       "",
+		"orcid_uri":   "",
+		"name":        "",
+		"given_names": "",
+		"family_name": "",
+		"country":     "",
+		"affiliation": "",
+		"department":  "",
+		"role_title":  "",
+	}
+
+	scenarios := []struct {
+		name        string
+		user        *AuthUser
+		employments []ORCIDAffiliation
+		expected    map[string]any
+	}{
+		{"nil user", nil, employments, empty},
+		{
+			"user without employments",
+			user,
+			nil,
+			map[string]any{
+				"orcid":       "0000-0002-1825-0097",
+				"orcid_uri":   "https://orcid.org/0000-0002-1825-0097",
+				"name":        "Josiah S. Carberry",
+				"given_names": "Josiah",
+				"family_name": "Carberry",
+				"country":     "US",
+				"affiliation": "",
+				"department":  "",
+				"role_title":  "",
+			},
+		},
+		{
+			"user with employments",
+			user,
+			employments,
+			map[string]any{
+				"orcid":       "0000-0002-1825-0097",
+				"orcid_uri":   "https://orcid.org/0000-0002-1825-0097",
+				"name":        "Josiah S. Carberry",
+				"given_names": "Josiah",
+				"family_name": "Carberry",
+				"country":     "US",
+				"affiliation": "Brown University",
+				"department":  "Psychoceramics",
+				"role_title":  "Professor",
+			},
+		},
+		{
+			"past employments only",
+			&AuthUser{Username: "0000-0002-1825-0097", RawUser: map[string]any{}},
+			employments[1:],
+			map[string]any{
+				"orcid":       "0000-0002-1825-0097",
+				"orcid_uri":   "",
+				"name":        "",
+				"given_names": "",
+				"family_name": "",
+				"country":     "",
+				"affiliation": "",
+				"department":  "",
+				"role_title":  "",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			fields := MapORCIDToRecord(s.user, s.employments)
+
+			if !reflect.DeepEqual(fields, s.expected) {
+				t.Fatalf("Expected fields\n%#v\ngot\n%#v", s.expected, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/sync/errgroup"
+)
+
+// ORCIDOAuthError defines an ORCID OAuth2 endpoint (revoke, introspect, etc.) error response.
+type ORCIDOAuthError struct {
+	URL         string `json:"-"`
+	Body        string `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+	Status      int    `json:"-"`
+}
+
+// Error implements the [error] interface.
+func (e *ORCIDOAuthError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("ORCID OAuth2 request to %s failed (%d): %s: %s", e.URL, e.Status, e.Code, e.Description)
+	}
+
+	return fmt.Sprintf("ORCID OAuth2 request to %s failed (%d):\n%s", e.URL, e.Status, e.Body)
+}
+
+// RevokeToken revokes the specified ORCID access or refresh token
+// (eg. when the user disconnects their ORCID account from the app).
+//
+// API reference: https://info.orcid.org/documentation/api-tutorials/api-tutorial-revoke-an-access-token/
+func (p *ORCID) RevokeToken(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.New("missing ORCID token to revoke")
+	}
+
+	_, err := p.postOAuthForm(ctx, p.siteURL()+"/oauth/revoke", url.Values{
+		"client_id":     {p.clientId},
+		"client_secret": {p.clientSecret},
+		"token":         {token},
+	})
+
+	return err
+}
+
+// ORCIDTokenIntrospection defines the RFC 7662 ORCID token introspection response.
+type ORCIDTokenIntrospection struct {
+	// Active reports whether the token is still valid.
+	//
+	// The other fields are usually empty for inactive tokens.
+	Active bool `json:"active"`
+
+	// Scope is the space separated list of the token scopes.
+	Scope string `json:"scope"`
+
+	// ClientId is the client_id of the app that requested the token.
+	ClientId string `json:"client_id"`
+
+	// TokenType is the token type (usually "bearer").
+	TokenType string `json:"token_type"`
+
+	// ORCID is the iD of the user that the token belongs to.
+	ORCID string `json:"orcid"`
+
+	// Exp is the token expiration time in epoch seconds.
+	Exp int64 `json:"exp"`
+}
+
+// Scopes returns the token scopes as slice.
+func (i *ORCIDTokenIntrospection) Scopes() []string {
+	return strings.Fields(i.Scope)
+}
+
+// Expires returns the token expiration time (zero if unknown).
+func (i *ORCIDTokenIntrospection) Expires() time.Time {
+	if i.Exp <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(i.Exp, 0)
+}
+
+// IntrospectToken checks the state of the specified ORCID access token
+// without calling the data API (eg. to proactively refresh tokens before they expire).
+//
+// See https://datatracker.ietf.org/doc/html/rfc7662
+func (p *ORCID) IntrospectToken(ctx context.Context, token string) (*ORCIDTokenIntrospection, error) {
+	if token == "" {
+		return nil, errors.New("missing ORCID token to introspect")
+	}
+
+	body, err := p.postOAuthForm(ctx, p.siteURL()+"/oauth/token/introspect", url.Values{
+		"client_id":     {p.clientId},
+		"client_secret": {p.clientSecret},
+		"token":         {token},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		ORCIDTokenIntrospection
+
+		// standard RFC 7662 fields that could hold the iD
+		Sub      string `json:"sub"`
+		Username string `json:"username"`
+	}{}
+	if err := json.Unmarshal(body, &extracted); err != nil {
+		return nil, err
+	}
+
+	result := extracted.ORCIDTokenIntrospection
+	if result.ORCID == "" {
+		result.ORCID = extracted.Sub
+	}
+	if result.ORCID == "" {
+		result.ORCID = extracted.Username
+	}
+
+	return &result, nil
+}
+
+// postOAuthForm sends a form POST request to the specified ORCID OAuth2
+// endpoint and returns its raw response body.
+func (p *ORCID) postOAuthForm(ctx context.Context, endpoint string, form url.Values) ([]byte, error) {
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.UserAgent != "" {
+		req.Header.Set("User-Agent", p.UserAgent)
+	}
+
+	res, err := p.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := p.readBody(endpoint, res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		oauthErr := &ORCIDOAuthError{}
+		if err := json.Unmarshal(body, oauthErr); err != nil {
+			oauthErr = &ORCIDOAuthError{}
+		}
+		oauthErr.URL = endpoint
+		oauthErr.Status = res.StatusCode
+		oauthErr.Body = string(body)
+
+		return nil, oauthErr
+	}
+
+	return body, nil
+}
+
+// FetchPublicPerson fetches the public person data of the specified
+// ORCID iD without a user token, using an app "/read-public"
+// client credentials token instead (eg. for back-office enrichment).
+//
+// The iD could be also specified as iD URI (see [NormalizeORCIDiD]).
+//
+// The returned AuthUser doesn't have any token fields.
+func (p *ORCID) FetchPublicPerson(ctx context.Context, iD string) (*AuthUser, error) {
+	iD, err := NormalizeORCIDiD(iD)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.ClientCredentialsToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.fetchPublicPerson(ctx, token, iD)
+}
+
+// FetchPublicPersons is the batch version of [ORCID.FetchPublicPerson]
+// (eg. for admin import tools).
+//
+// The iDs are fetched concurrently with up to [ORCID.BatchConcurrency] workers
+// sharing a single client credentials token, the person cache and the retries
+// of the rate limited requests.
+//
+// It returns the fetched persons and the per iD errors keyed by the
+// normalized iDs (see [NormalizeORCIDiD]), except the invalid iDs errors
+// that are keyed by the requested value.
+// Duplicated iDs (including the same iD in different forms, eg. with
+// and without the "https://orcid.org/" prefix) are fetched only once.
+func (p *ORCID) FetchPublicPersons(ctx context.Context, iDs []string) (map[string]*AuthUser, map[string]error) {
+	users := make(map[string]*AuthUser, len(iDs))
+	errs := map[string]error{}
+
+	valid := make([]string, 0, len(iDs))
+	for _, iD := range iDs {
+		normalizedId, err := NormalizeORCIDiD(iD)
+		if err != nil {
+			errs[iD] = err
+			continue
+		}
+
+		if !slices.Contains(valid, normalizedId) {
+			valid = append(valid, normalizedId)
+		}
+	}
+
+	if len(valid) == 0 {
+		return users, errs
+	}
+
+	// obtain the token upfront so that the workers don't request it concurrently
+	token, err := p.ClientCredentialsToken(ctx)
+	if err != nil {
+		for _, iD := range valid {
+			errs[iD] = err
+		}
+		return users, errs
+	}
+
+	concurrency := p.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = ORCIDDefaultBatchConcurrency
+	}
+
+	var mu sync.Mutex
+
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+
+	for _, iD := range valid {
+		group.Go(func() error {
+			user, err := p.fetchPublicPerson(ctx, token, iD)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs[iD] = err
+			} else {
+				users[iD] = user
+			}
+
+			return nil
+		})
+	}
+
+	group.Wait()
+
+	return users, errs
+}
+
+// fetchPublicPerson fetches the public person data of the (already validated)
+// iD with the specified client credentials token.
+//
+// The client credentials tokens are always of public class so that
+// the public lookups never share the person cache with the member logins.
+func (p *ORCID) fetchPublicPerson(ctx context.Context, token *oauth2.Token, iD string) (*AuthUser, error) {
+	person, err := p.fetchPersonWithClass(ctx, token, iD, orcidTokenClassPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	name := person.name
+	if name == "" && !p.SkipNameFallback {
+		name = iD
+	}
+
+	return p.newAuthUser(&oauth2.Token{}, iD, name, person.email, person.rawUser), nil
+}
+
+// orcidClientTokenExpiryDelta specifies how long before its expiration
+// a cached client credentials token is considered expired.
+const orcidClientTokenExpiryDelta = 1 * time.Minute
+
+// ClientCredentialsToken returns an app "/read-public" client credentials token
+// that could be used for non-interactive access to the ORCID public data
+// (eg. [ORCID.FetchPublicPerson]).
+//
+// The token is cached (and shared between the provider instances with
+// the same token url and client id) until shortly before its expiration.
+//
+// API reference: https://info.orcid.org/documentation/api-tutorials/api-tutorial-read-data-on-a-record/#Get_an_access_token
+func (p *ORCID) ClientCredentialsToken(ctx context.Context) (*oauth2.Token, error) {
+	if p.clientId == "" || p.clientSecret == "" {
+		return nil, errors.New("the ORCID client credentials token requires client id and secret")
+	}
+
+	cacheKey := orcidCachePrefixToken + p.tokenURL + "#" + p.clientId
+
+	if raw, ok := p.cache().Get(cacheKey); ok {
+		cached := &oauth2.Token{}
+		if err := json.Unmarshal(raw, cached); err == nil &&
+			(cached.Expiry.IsZero() || time.Now().Add(orcidClientTokenExpiryDelta).Before(cached.Expiry)) {
+			return cached, nil
+		}
+	}
+
+	token, err := p.requestClientCredentialsToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ttl time.Duration
+	if !token.Expiry.IsZero() {
+		ttl = time.Until(token.Expiry) - orcidClientTokenExpiryDelta
+	}
+
+	// tokens that are already about to expire are not cached
+	if token.Expiry.IsZero() || ttl > 0 {
+		if raw, err := json.Marshal(token); err == nil {
+			p.cache().Set(cacheKey, raw, ttl)
+		}
+	}
+
+	return token, nil
+}
+
+// requestClientCredentialsToken requests a new "/read-public" client credentials token.
+func (p *ORCID) requestClientCredentialsToken(ctx context.Context) (*oauth2.Token, error) {
+
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	config := &clientcredentials.Config{
+		ClientID:     p.clientId,
+		ClientSecret: p.clientSecret,
+		TokenURL:     p.tokenURL,
+		Scopes:       []string{ORCIDScopeReadPublic},
+		AuthStyle:    p.TokenAuthStyle,
+	}
+
+	var token *oauth2.Token
+
+	err := p.retry(ctx, func() error {
+		var err error
+		token, err = config.Token(p.clientCtx(ctx))
+		return err
+	})
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode != "" {
+			return nil, fmt.Errorf(
+				"failed to obtain ORCID client credentials token (%s): %s: %w",
+				retrieveErr.ErrorCode,
+				retrieveErr.ErrorDescription,
+				err,
+			)
+		}
+
+		return nil, fmt.Errorf("failed to obtain ORCID client credentials token: %w", err)
+	}
+
+	return token, nil
+}
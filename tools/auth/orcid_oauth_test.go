@@ -0,0 +1,514 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestORCIDRevokeToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth/revoke" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if r.PostForm.Get("client_id") != "test_client_id" || r.PostForm.Get("client_secret") != "test_client_secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"Client not found"}`)
+			return
+		}
+
+		if r.PostForm.Get("token") != "valid_token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_request","error_description":"Invalid token"}`)
+			return
+		}
+
+		// success with no body
+	}))
+	defer srv.Close()
+
+	scenarios := []struct {
+		name         string
+		clientSecret string
+		token        string
+		expectedCode string
+		expectError  bool
+	}{
+		{"empty token", "test_client_secret", "", "", true},
+		{"invalid client", "invalid", "valid_token", "invalid_client", true},
+		{"invalid token", "test_client_secret", "invalid_token", "invalid_request", true},
+		{"valid token", "test_client_secret", "valid_token", "", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewORCIDProvider()
+			p.SiteURL = srv.URL
+			p.SetClientId("test_client_id")
+			p.SetClientSecret(s.clientSecret)
+
+			err := p.RevokeToken(context.Background(), s.token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if s.expectedCode == "" {
+				return
+			}
+
+			var oauthErr *ORCIDOAuthError
+			if !errors.As(err, &oauthErr) || oauthErr.Code != s.expectedCode {
+				t.Fatalf("Expected ORCIDOAuthError with %q code, got %v", s.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestORCIDIntrospectToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth/token/introspect" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.PostForm.Get("token") {
+		case "active_token":
+			fmt.Fprint(w, `{"active":true,"scope":"/read-limited /activities/update","client_id":"test_client_id","token_type":"bearer","exp":1924992000,"iat":1704067200,"sub":"0000-0002-1825-0097"}`)
+		case "orcid_token":
+			fmt.Fprint(w, `{"active":true,"scope":"/authenticate","orcid":"0000-0002-1694-233X","exp":1924992000}`)
+		case "error_token":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"Bad client credentials"}`)
+		default:
+			fmt.Fprint(w, `{"active":false}`)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.SiteURL = srv.URL
+	p.SetClientId("test_client_id")
+	p.SetClientSecret("test_client_secret")
+
+	t.Run("empty token", func(t *testing.T) {
+		if _, err := p.IntrospectToken(context.Background(), ""); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("active token", func(t *testing.T) {
+		result, err := p.IntrospectToken(context.Background(), "active_token")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !result.Active {
+			t.Fatal("Expected active token")
+		}
+
+		if scopes := result.Scopes(); !slices.Equal(scopes, []string{"/read-limited", "/activities/update"}) {
+			t.Fatalf("Expected the token scopes, got %v", scopes)
+		}
+
+		if result.ORCID != "0000-0002-1825-0097" {
+			t.Fatalf("Expected the sub iD, got %q", result.ORCID)
+		}
+
+		if expires := result.Expires(); !expires.Equal(time.Unix(1924992000, 0)) {
+			t.Fatalf("Expected exp 1924992000, got %v", expires)
+		}
+	})
+
+	t.Run("active token with orcid field", func(t *testing.T) {
+		result, err := p.IntrospectToken(context.Background(), "orcid_token")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.ORCID != "0000-0002-1694-233X" {
+			t.Fatalf("Expected the orcid iD, got %q", result.ORCID)
+		}
+	})
+
+	t.Run("inactive token", func(t *testing.T) {
+		result, err := p.IntrospectToken(context.Background(), "inactive_token")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Active || result.ORCID != "" || len(result.Scopes()) != 0 || !result.Expires().IsZero() {
+			t.Fatalf("Expected inactive token without details, got %#v", result)
+		}
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		_, err := p.IntrospectToken(context.Background(), "error_token")
+
+		var oauthErr *ORCIDOAuthError
+		if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_client" || oauthErr.Status != http.StatusUnauthorized {
+			t.Fatalf("Expected invalid_client ORCIDOAuthError, got %v", err)
+		}
+	})
+}
+
+func TestORCIDFetchPublicPerson(t *testing.T) {
+	var tokenRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/oauth/token":
+			tokenRequests.Add(1)
+
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+
+			if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "/read-public" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_request","error_description":"Unexpected grant"}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"access_token":"public_token","token_type":"bearer","expires_in":631138518,"scope":"/read-public"}`)
+		case "/v3.0/0000-0002-1825-0097/person":
+			if r.Header.Get("Authorization") != "Bearer public_token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			fmt.Fprint(w, testORCIDPersonJSON)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.SetClientId("test_client_id")
+	p.SetClientSecret("test_client_secret")
+	p.SetTokenURL(srv.URL + "/oauth/token")
+	p.APIBaseURL = srv.URL
+
+	t.Run("invalid iD", func(t *testing.T) {
+		_, err := p.FetchPublicPerson(context.Background(), "0000-0002-1825-0098")
+		if !errors.Is(err, ErrInvalidORCIDiD) {
+			t.Fatalf("Expected ErrInvalidORCIDiD, got %v", err)
+		}
+
+		if total := tokenRequests.Load(); total != 0 {
+			t.Fatalf("Expected no token requests, got %d", total)
+		}
+	})
+
+	t.Run("missing client credentials", func(t *testing.T) {
+		p2 := NewORCIDProvider()
+		p2.SetTokenURL(srv.URL + "/oauth/token")
+
+		if _, err := p2.FetchPublicPerson(context.Background(), "0000-0002-1825-0097"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("valid iD", func(t *testing.T) {
+		user, err := p.FetchPublicPerson(context.Background(), " https://orcid.org/0000-0002-1825-0097/")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user.Id != "0000-0002-1825-0097" || user.Name != "Josiah S. Carberry" || user.Email != "josiah@example.com" {
+			t.Fatalf("Expected the public person data, got %#v", user)
+		}
+
+		if user.AccessToken != "" || user.RefreshToken != "" {
+			t.Fatalf("Expected no token fields, got %q and %q", user.AccessToken, user.RefreshToken)
+		}
+
+		if total := tokenRequests.Load(); total != 1 {
+			t.Fatalf("Expected 1 token request, got %d", total)
+		}
+	})
+}
+
+func TestORCIDClientCredentialsToken(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		response         string
+		status           int
+		expectError      bool
+		expectedRequests int32
+	}{
+		{
+			"long lived token (cached)",
+			`{"access_token":"client_token","token_type":"bearer","expires_in":631138518,"scope":"/read-public"}`,
+			http.StatusOK,
+			false,
+			1,
+		},
+		{
+			"token expiring within the expiry delta (refreshed)",
+			`{"access_token":"client_token","token_type":"bearer","expires_in":30,"scope":"/read-public"}`,
+			http.StatusOK,
+			false,
+			3,
+		},
+		{
+			"ORCID error response",
+			`{"error":"invalid_client","error_description":"Client not found: test_client_id"}`,
+			http.StatusUnauthorized,
+			true,
+			3,
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var totalRequests atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				totalRequests.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(s.status)
+				fmt.Fprint(w, s.response)
+			}))
+			defer srv.Close()
+
+			for j := 0; j < 3; j++ {
+				// new provider instance per call similar to PocketBase
+				p := NewORCIDProvider()
+				p.SetClientId(fmt.Sprintf("test_client_id_%d", i))
+				p.SetClientSecret("test_client_secret")
+				p.SetTokenURL(srv.URL)
+				p.TokenAuthStyle = oauth2.AuthStyleInParams // avoid the auto-detect second request
+
+				token, err := p.ClientCredentialsToken(context.Background())
+
+				hasErr := err != nil
+				if hasErr != s.expectError {
+					t.Fatalf("[%d] Expected hasErr %v, got %v (%v)", j, s.expectError, hasErr, err)
+				}
+
+				if hasErr {
+					if !strings.Contains(err.Error(), "invalid_client") {
+						t.Fatalf("[%d] Expected the ORCID error code, got %v", j, err)
+					}
+					continue
+				}
+
+				if token.AccessToken != "client_token" {
+					t.Fatalf("[%d] Expected client_token, got %q", j, token.AccessToken)
+				}
+			}
+
+			if total := totalRequests.Load(); total != s.expectedRequests {
+				t.Fatalf("Expected %d token requests, got %d", s.expectedRequests, total)
+			}
+		})
+	}
+}
+
+func TestORCIDTokenAuthStyle(t *testing.T) {
+	// the mock token server accepts the client credentials only in the
+	// configured location, similar to the ORCID client registrations
+	newTokenServer := func(inHeader bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			r.ParseForm()
+
+			clientId, clientSecret, hasBasic := r.BasicAuth()
+			if !inHeader {
+				clientId, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+			}
+
+			if (inHeader != hasBasic) || clientId != "test_client_id" || clientSecret != "test_client_secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_client","error_description":"Bad client credentials"}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"access_token":"new_token","token_type":"bearer","expires_in":3600,"orcid":"0000-0002-1825-0097"}`)
+		}))
+	}
+
+	scenarios := []struct {
+		name        string
+		inHeader    bool
+		style       oauth2.AuthStyle
+		expectError bool
+	}{
+		{"header server with InHeader", true, oauth2.AuthStyleInHeader, false},
+		{"header server with InParams", true, oauth2.AuthStyleInParams, true},
+		{"header server with AutoDetect", true, oauth2.AuthStyleAutoDetect, false},
+		{"params server with InParams", false, oauth2.AuthStyleInParams, false},
+		{"params server with InHeader", false, oauth2.AuthStyleInHeader, true},
+		{"params server with AutoDetect", false, oauth2.AuthStyleAutoDetect, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTokenServer(s.inHeader)
+			defer srv.Close()
+
+			p := NewORCIDProvider(WithORCIDTokenAuthStyle(s.style))
+			p.SetClientId("test_client_id")
+			p.SetClientSecret("test_client_secret")
+			p.SetTokenURL(srv.URL)
+			p.MaxRetries = 0
+
+			flows := map[string]func() (*oauth2.Token, error){
+				"refresh": func() (*oauth2.Token, error) {
+					return p.RefreshToken(context.Background(), "test_refresh_token")
+				},
+				"client credentials": func() (*oauth2.Token, error) {
+					return p.requestClientCredentialsToken(context.Background())
+				},
+				"code exchange": func() (*oauth2.Token, error) {
+					return p.FetchToken("test_code")
+				},
+			}
+
+			for name, flow := range flows {
+				token, err := flow()
+
+				hasErr := err != nil
+				if hasErr != s.expectError {
+					t.Fatalf("[%s] Expected hasErr %v, got %v (%v)", name, s.expectError, hasErr, err)
+				}
+
+				if hasErr {
+					if !strings.Contains(err.Error(), "invalid_client") {
+						t.Fatalf("[%s] Expected invalid_client error, got %v", name, err)
+					}
+					continue
+				}
+
+				if token.AccessToken != "new_token" {
+					t.Fatalf("[%s] Expected new_token, got %q", name, token.AccessToken)
+				}
+			}
+		})
+	}
+}
+
+func TestORCIDFetchPublicPersons(t *testing.T) {
+	var tokenRequests, inflight, maxInflight atomic.Int32
+
+	var personRequestsMu sync.Mutex
+	personRequests := map[string]int{}
+
+	validIds := []string{testORCIDiD(1), testORCIDiD(2), testORCIDiD(3), testORCIDiD(4), testORCIDiD(5)}
+	missingId := testORCIDiD(404)
+	invalidId := "0000-0002-1825-0098"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests.Add(1)
+			fmt.Fprint(w, `{"access_token":"public_token","token_type":"bearer","expires_in":631138518,"scope":"/read-public"}`)
+			return
+		}
+
+		personRequestsMu.Lock()
+		personRequests[r.URL.Path]++
+		personRequestsMu.Unlock()
+
+		current := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			prev := maxInflight.Load()
+			if current <= prev || maxInflight.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		for _, iD := range validIds {
+			if r.URL.Path == "/v3.0/"+iD+"/person" {
+				fmt.Fprintf(w, `{"name":{"given-names":{"value":"User %s"}}}`, iD)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"response-code":404,"developer-message":"404 Not Found","error-code":9016}`)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.SetClientId("test_client_id_batch")
+	p.SetClientSecret("test_client_secret")
+	p.SetTokenURL(srv.URL + "/oauth/token")
+	p.APIBaseURL = srv.URL
+	p.BatchConcurrency = 2
+
+	// with duplicated iDs in the same and in different forms
+	iDs := append([]string{invalidId, missingId, validIds[0], "https://orcid.org/" + validIds[1], " " + strings.ReplaceAll(validIds[2], "-", "") + " "}, validIds...)
+
+	users, errs := p.FetchPublicPersons(context.Background(), iDs)
+
+	if len(users) != len(validIds) {
+		t.Fatalf("Expected %d users, got %d (%v)", len(validIds), len(users), errs)
+	}
+
+	for _, iD := range validIds {
+		if users[iD] == nil || users[iD].Name != "User "+iD {
+			t.Fatalf("Expected user %q, got %#v", iD, users[iD])
+		}
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+
+	for path, total := range personRequests {
+		if total != 1 {
+			t.Fatalf("Expected 1 request for %s, got %d", path, total)
+		}
+	}
+
+	if !errors.Is(errs[invalidId], ErrInvalidORCIDiD) {
+		t.Fatalf("Expected ErrInvalidORCIDiD, got %v", errs[invalidId])
+	}
+
+	var apiErr *ORCIDAPIError
+	if !errors.As(errs[missingId], &apiErr) || apiErr.Status != http.StatusNotFound {
+		t.Fatalf("Expected 404 ORCIDAPIError, got %v", errs[missingId])
+	}
+
+	if total := tokenRequests.Load(); total != 1 {
+		t.Fatalf("Expected 1 token request, got %d", total)
+	}
+
+	if max := maxInflight.Load(); max > 2 {
+		t.Fatalf("Expected at most 2 concurrent requests, got %d", max)
+	}
+}
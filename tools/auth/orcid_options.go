@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ORCIDOption defines a single [NewORCIDProvider] configuration option.
+type ORCIDOption func(p *ORCID)
+
+// WithORCIDSandbox configures the provider to operate against
+// the ORCID sandbox (https://sandbox.orcid.org) environment.
+func WithORCIDSandbox() ORCIDOption {
+	return func(p *ORCID) {
+		p.authURL = orcidSandboxSiteURL + "/oauth/authorize"
+		p.tokenURL = orcidSandboxSiteURL + "/oauth/token"
+		p.SiteURL = orcidSandboxSiteURL
+
+		if p.member {
+			p.displayName = "ORCID (sandbox member)"
+			p.APIBaseURL = orcidSandboxMemberAPIBaseURL
+			p.MemberAPIBaseURL = orcidSandboxMemberAPIBaseURL
+		} else {
+			p.displayName = "ORCID (sandbox)"
+			p.APIBaseURL = orcidSandboxAPIBaseURL
+		}
+	}
+}
+
+// WithORCIDMember configures the provider to use the ORCID member API
+// and to request the "/read-limited" scope (see [NewORCIDMemberProvider]).
+//
+// It could be combined with [WithORCIDSandbox] (in any order) to
+// use the sandbox member API (https://api.sandbox.orcid.org).
+func WithORCIDMember() ORCIDOption {
+	return func(p *ORCID) {
+		p.member = true
+
+		if p.SiteURL == orcidSandboxSiteURL {
+			p.displayName = "ORCID (sandbox member)"
+			p.APIBaseURL = orcidSandboxMemberAPIBaseURL
+		} else {
+			p.displayName = "ORCID (member)"
+			p.APIBaseURL = ORCIDDefaultMemberAPIBaseURL
+		}
+		p.MemberAPIBaseURL = p.APIBaseURL
+
+		if !slices.Contains(p.scopes, ORCIDScopeReadLimited) {
+			p.scopes = append(p.scopes, ORCIDScopeReadLimited)
+		}
+	}
+}
+
+// WithORCIDPKCE toggles the PKCE flow of the provider (enabled by default).
+//
+// PKCE protects the authorization code from interception and should be
+// disabled only for legacy ORCID client registrations or confidential
+// server-side flows that reject the code_challenge parameters.
+// In that case the client secret remains the only protection of the code exchange.
+func WithORCIDPKCE(enable bool) ORCIDOption {
+	return func(p *ORCID) {
+		p.SetPKCE(enable)
+	}
+}
+
+// WithORCIDScopes configures the provider to request the specified
+// scopes in addition to its current ones (aka. "/authenticate", "openid"
+// and "/read-limited" for member providers are always kept).
+//
+// Empty and unknown scopes are not requested and are reported
+// by [ORCID.OptionsError] (and logged if the provider has a Logger).
+// Use [ORCID.SetScopes] if you want to explicitly remove a default scope.
+func WithORCIDScopes(scopes ...string) ORCIDOption {
+	return func(p *ORCID) {
+		for _, scope := range scopes {
+			if err := validateORCIDScopes([]string{scope}); err != nil {
+				p.optionsErr = errors.Join(p.optionsErr, err)
+				continue
+			}
+
+			if !slices.Contains(p.scopes, scope) {
+				p.scopes = append(p.scopes, scope)
+			}
+		}
+	}
+}
+
+// WithORCIDAuthParams adds the specified parameters to the provider AuthParams.
+func WithORCIDAuthParams(params map[string]string) ORCIDOption {
+	return func(p *ORCID) {
+		if p.AuthParams == nil {
+			p.AuthParams = make(map[string]string, len(params))
+		}
+
+		for key, value := range params {
+			p.AuthParams[key] = value
+		}
+	}
+}
+
+// WithORCIDTimeout sets the provider Timeout.
+func WithORCIDTimeout(timeout time.Duration) ORCIDOption {
+	return func(p *ORCID) {
+		p.Timeout = timeout
+	}
+}
+
+// WithORCIDHTTPClient sets a custom provider HTTP client (see [ORCID.SetClient]).
+func WithORCIDHTTPClient(client *http.Client) ORCIDOption {
+	return func(p *ORCID) {
+		p.SetClient(client)
+	}
+}
+
+// WithORCIDProxy routes the provider requests through the specified
+// HTTP(S) proxy (see [ORCID.SetProxy]).
+//
+// Note that it should be applied after [WithORCIDHTTPClient] (if any),
+// otherwise the proxy transport will be replaced by the custom client.
+func WithORCIDProxy(proxyURL string) ORCIDOption {
+	return func(p *ORCID) {
+		p.SetProxy(proxyURL)
+	}
+}
+
+// WithORCIDUserAgent sets the provider UserAgent.
+func WithORCIDUserAgent(userAgent string) ORCIDOption {
+	return func(p *ORCID) {
+		p.UserAgent = userAgent
+	}
+}
+
+// WithORCIDAcceptLanguage sets the provider AcceptLanguage.
+func WithORCIDAcceptLanguage(acceptLanguage string) ORCIDOption {
+	return func(p *ORCID) {
+		p.AcceptLanguage = acceptLanguage
+	}
+}
+
+// WithORCIDRetries sets the provider MaxRetries and RetryBaseDelay.
+func WithORCIDRetries(maxRetries int, baseDelay time.Duration) ORCIDOption {
+	return func(p *ORCID) {
+		p.MaxRetries = maxRetries
+		p.RetryBaseDelay = baseDelay
+	}
+}
+
+// WithORCIDAPIBaseURL sets the provider APIBaseURL
+// (eg. to use a mock server in tests).
+func WithORCIDAPIBaseURL(apiBaseURL string) ORCIDOption {
+	return func(p *ORCID) {
+		p.APIBaseURL = apiBaseURL
+	}
+}
+
+// WithORCIDAPIVersion sets the provider APIVersion
+// (eg. [ORCIDAPIVersion21] for integrations pinned to the v2.1 responses).
+func WithORCIDAPIVersion(apiVersion string) ORCIDOption {
+	return func(p *ORCID) {
+		p.APIVersion = apiVersion
+	}
+}
+
+// WithORCIDNameStrategy sets the provider NameStrategy.
+func WithORCIDNameStrategy(strategy ORCIDNameStrategy) ORCIDOption {
+	return func(p *ORCID) {
+		p.NameStrategy = strategy
+	}
+}
+
+// WithORCIDStrictEmail enables the provider StrictEmail.
+func WithORCIDStrictEmail() ORCIDOption {
+	return func(p *ORCID) {
+		p.StrictEmail = true
+	}
+}
+
+// WithORCIDSkipEmail enables the provider SkipEmail.
+func WithORCIDSkipEmail() ORCIDOption {
+	return func(p *ORCID) {
+		p.SkipEmail = true
+	}
+}
+
+// WithORCIDRecord enables the provider UseRecord.
+func WithORCIDRecord() ORCIDOption {
+	return func(p *ORCID) {
+		p.UseRecord = true
+	}
+}
+
+// WithORCIDURIAsId enables the provider URIAsId.
+func WithORCIDURIAsId() ORCIDOption {
+	return func(p *ORCID) {
+		p.URIAsId = true
+	}
+}
+
+// WithORCIDCache sets the provider Cache backend and enables
+// the person cache for the specified ttl (see [ORCID.PersonCacheTTL]).
+func WithORCIDCache(cache ORCIDCache, personTTL time.Duration) ORCIDOption {
+	return func(p *ORCID) {
+		p.Cache = cache
+		p.PersonCacheTTL = personTTL
+	}
+}
+
+// WithORCIDConditionalRequests enables the provider ConditionalRequests
+// (the responses are stored in the provider Cache, see [WithORCIDCache]).
+func WithORCIDConditionalRequests() ORCIDOption {
+	return func(p *ORCID) {
+		p.ConditionalRequests = true
+	}
+}
+
+// WithORCIDLogger sets the provider Logger.
+func WithORCIDLogger(logger *slog.Logger) ORCIDOption {
+	return func(p *ORCID) {
+		p.Logger = logger
+	}
+}
+
+// WithORCIDMetrics sets the provider Metrics hook.
+func WithORCIDMetrics(metrics ORCIDMetrics) ORCIDOption {
+	return func(p *ORCID) {
+		p.Metrics = metrics
+	}
+}
+
+// WithORCIDMaxBodySize sets the max number of bytes read from a single
+// ORCID API response body (0 disables the limit).
+func WithORCIDMaxBodySize(maxBodySize int64) ORCIDOption {
+	return func(p *ORCID) {
+		p.MaxBodySize = maxBodySize
+	}
+}
+
+// WithORCIDFollowPrimaryRecord enables the provider FollowPrimaryRecord setting.
+func WithORCIDFollowPrimaryRecord() ORCIDOption {
+	return func(p *ORCID) {
+		p.FollowPrimaryRecord = true
+	}
+}
+
+// WithORCIDTokenAuthStyle sets the provider TokenAuthStyle
+// (eg. [oauth2.AuthStyleInParams]).
+func WithORCIDTokenAuthStyle(style oauth2.AuthStyle) ORCIDOption {
+	return func(p *ORCID) {
+		p.TokenAuthStyle = style
+	}
+}
+
+// WithORCIDStrictDecoding enables the provider StrictDecoding setting.
+func WithORCIDStrictDecoding() ORCIDOption {
+	return func(p *ORCID) {
+		p.StrictDecoding = true
+	}
+}
+
+// WithORCIDGravatar enables the provider Gravatar setting
+// with the specified default image (could be empty).
+func WithORCIDGravatar(defaultImage string) ORCIDOption {
+	return func(p *ORCID) {
+		p.Gravatar = true
+		p.GravatarDefault = defaultImage
+	}
+}
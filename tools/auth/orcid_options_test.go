@@ -0,0 +1,454 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewORCIDProviderDefaults(t *testing.T) {
+	p := NewORCIDProvider()
+
+	if p.APIBaseURL != ORCIDDefaultAPIBaseURL {
+		t.Fatalf("Expected APIBaseURL %q, got %q", ORCIDDefaultAPIBaseURL, p.APIBaseURL)
+	}
+
+	if p.SiteURL != ORCIDDefaultSiteURL {
+		t.Fatalf("Expected SiteURL %q, got %q", ORCIDDefaultSiteURL, p.SiteURL)
+	}
+
+	if p.Timeout != 15*time.Second {
+		t.Fatalf("Expected 15s Timeout, got %v", p.Timeout)
+	}
+
+	if expected := []string{ORCIDScopeAuthenticate, ORCIDScopeOpenId}; !slices.Equal(p.Scopes(), expected) {
+		t.Fatalf("Expected scopes %v, got %v", expected, p.Scopes())
+	}
+
+	if p.HTTPClient() != http.DefaultClient {
+		t.Fatal("Expected the default HTTP client")
+	}
+}
+
+func TestNewORCIDProviderOptions(t *testing.T) {
+	client := &http.Client{}
+
+	p := NewORCIDProvider(
+		WithORCIDSandbox(),
+		WithORCIDScopes(ORCIDScopeReadLimited, "/unknown", ORCIDScopeReadLimited),
+		WithORCIDTimeout(5*time.Second),
+		WithORCIDHTTPClient(client),
+		WithORCIDRetries(5, time.Second),
+		WithORCIDStrictEmail(),
+		WithORCIDSkipEmail(),
+		WithORCIDRecord(),
+		WithORCIDURIAsId(),
+	)
+
+	if p.DisplayName() != "ORCID (sandbox)" {
+		t.Fatalf("Expected sandbox display name, got %q", p.DisplayName())
+	}
+
+	if p.AuthURL() != "https://sandbox.orcid.org/oauth/authorize" {
+		t.Fatalf("Expected sandbox auth url, got %q", p.AuthURL())
+	}
+
+	if p.TokenURL() != "https://sandbox.orcid.org/oauth/token" {
+		t.Fatalf("Expected sandbox token url, got %q", p.TokenURL())
+	}
+
+	if p.APIBaseURL != "https://pub.sandbox.orcid.org" {
+		t.Fatalf("Expected sandbox APIBaseURL, got %q", p.APIBaseURL)
+	}
+
+	if p.SiteURL != "https://sandbox.orcid.org" {
+		t.Fatalf("Expected sandbox SiteURL, got %q", p.SiteURL)
+	}
+
+	if expected := []string{ORCIDScopeAuthenticate, ORCIDScopeOpenId, ORCIDScopeReadLimited}; !slices.Equal(p.Scopes(), expected) {
+		t.Fatalf("Expected scopes %v, got %v", expected, p.Scopes())
+	}
+
+	if err := p.OptionsError(); err == nil || !strings.Contains(err.Error(), `"/unknown"`) {
+		t.Fatalf("Expected the unknown scope to be reported, got %v", err)
+	}
+
+	if p.Timeout != 5*time.Second {
+		t.Fatalf("Expected 5s Timeout, got %v", p.Timeout)
+	}
+
+	if p.HTTPClient() != client {
+		t.Fatal("Expected the custom HTTP client")
+	}
+
+	if p.MaxRetries != 5 || p.RetryBaseDelay != time.Second {
+		t.Fatalf("Expected 5 retries with 1s base delay, got %d and %v", p.MaxRetries, p.RetryBaseDelay)
+	}
+
+	if !p.StrictEmail || !p.SkipEmail || !p.UseRecord || !p.URIAsId {
+		t.Fatalf("Expected StrictEmail, SkipEmail, UseRecord and URIAsId to be enabled, got %v, %v, %v and %v", p.StrictEmail, p.SkipEmail, p.UseRecord, p.URIAsId)
+	}
+}
+
+func TestWithORCIDScopesEmpty(t *testing.T) {
+	p := NewORCIDProvider(WithORCIDScopes())
+
+	if expected := []string{ORCIDScopeAuthenticate, ORCIDScopeOpenId}; !slices.Equal(p.Scopes(), expected) {
+		t.Fatalf("Expected the default scopes %v, got %v", expected, p.Scopes())
+	}
+}
+
+func TestWithORCIDScopesInvalid(t *testing.T) {
+	var logs bytes.Buffer
+
+	p := NewORCIDProvider(
+		WithORCIDLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithORCIDScopes("", "/unknown", ORCIDScopeActivitiesUpdate),
+	)
+
+	if expected := []string{ORCIDScopeAuthenticate, ORCIDScopeOpenId, ORCIDScopeActivitiesUpdate}; !slices.Equal(p.Scopes(), expected) {
+		t.Fatalf("Expected scopes %v, got %v", expected, p.Scopes())
+	}
+
+	err := p.OptionsError()
+	if err == nil || !strings.Contains(err.Error(), "empty") || !strings.Contains(err.Error(), `"/unknown"`) {
+		t.Fatalf("Expected the empty and unknown scopes to be reported, got %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "Invalid ORCID provider options") {
+		t.Fatalf("Expected the invalid options to be logged, got %q", logs.String())
+	}
+
+	if err := NewORCIDProvider(WithORCIDScopes(ORCIDScopeReadPublic)).OptionsError(); err != nil {
+		t.Fatalf("Expected nil options error, got %v", err)
+	}
+}
+
+func TestWithORCIDMemberAndScopes(t *testing.T) {
+	srv := newTestORCIDServer(t)
+
+	p := srv.provider(WithORCIDMember(), WithORCIDScopes(ORCIDScopeActivitiesUpdate))
+
+	if !p.IsMember() {
+		t.Fatal("Expected member provider")
+	}
+
+	expected := []string{ORCIDScopeAuthenticate, ORCIDScopeOpenId, ORCIDScopeReadLimited, ORCIDScopeActivitiesUpdate}
+	if !slices.Equal(p.Scopes(), expected) {
+		t.Fatalf("Expected scopes %v, got %v", expected, p.Scopes())
+	}
+
+	// the token is granted with the requested scopes
+	token := srv.token().WithExtra(map[string]any{
+		"orcid": testORCIDServeriD,
+		"scope": strings.Join(p.Scopes(), " "),
+	})
+
+	if _, err := p.FetchAuthUser(token); err != nil {
+		t.Fatalf("Expected nil member fetch error, got %v", err)
+	}
+}
+
+func TestNewORCIDMemberProvider(t *testing.T) {
+	scenarios := []struct {
+		name                string
+		provider            *ORCID
+		expectedDisplayName string
+		expectedAPIURL      string
+		expectedTokenURL    string
+	}{
+		{
+			"member",
+			NewORCIDMemberProvider(),
+			"ORCID (member)",
+			"https://api.orcid.org/v3.0/0000-0002-1825-0097/person",
+			"https://orcid.org/oauth/token",
+		},
+		{
+			"member + sandbox",
+			NewORCIDProvider(WithORCIDMember(), WithORCIDSandbox()),
+			"ORCID (sandbox member)",
+			"https://api.sandbox.orcid.org/v3.0/0000-0002-1825-0097/person",
+			"https://sandbox.orcid.org/oauth/token",
+		},
+		{
+			"sandbox + member",
+			NewORCIDProvider(WithORCIDSandbox(), WithORCIDMember()),
+			"ORCID (sandbox member)",
+			"https://api.sandbox.orcid.org/v3.0/0000-0002-1825-0097/person",
+			"https://sandbox.orcid.org/oauth/token",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if !s.provider.IsMember() {
+				t.Fatal("Expected member provider")
+			}
+
+			if s.provider.DisplayName() != s.expectedDisplayName {
+				t.Fatalf("Expected display name %q, got %q", s.expectedDisplayName, s.provider.DisplayName())
+			}
+
+			if v := s.provider.apiURL("0000-0002-1825-0097", "/person"); v != s.expectedAPIURL {
+				t.Fatalf("Expected api url %q, got %q", s.expectedAPIURL, v)
+			}
+
+			if s.provider.TokenURL() != s.expectedTokenURL {
+				t.Fatalf("Expected token url %q, got %q", s.expectedTokenURL, s.provider.TokenURL())
+			}
+
+			if !slices.Contains(s.provider.Scopes(), ORCIDScopeReadLimited) {
+				t.Fatalf("Expected %q scope, got %v", ORCIDScopeReadLimited, s.provider.Scopes())
+			}
+		})
+	}
+
+	if NewORCIDProvider().IsMember() {
+		t.Fatal("Expected the default provider to not be a member provider")
+	}
+}
+
+func TestWithORCIDAPIVersion(t *testing.T) {
+	scenarios := []struct {
+		version           string
+		expectedAPIURL    string
+		expectedStatusURL string
+		expectError       bool
+	}{
+		{"", "https://pub.orcid.org/v3.0/0000-0002-1825-0097/person", "https://pub.orcid.org/v3.0/status", false},
+		{"v3.0", "https://pub.orcid.org/v3.0/0000-0002-1825-0097/person", "https://pub.orcid.org/v3.0/status", false},
+		{"3.0", "https://pub.orcid.org/v3.0/0000-0002-1825-0097/person", "https://pub.orcid.org/v3.0/status", false},
+		{"v2.1", "https://pub.orcid.org/v2.1/0000-0002-1825-0097/person", "https://pub.orcid.org/v2.1/status", false},
+		{"/V2.1/", "https://pub.orcid.org/v2.1/0000-0002-1825-0097/person", "https://pub.orcid.org/v2.1/status", false},
+		{"v4.0", "https://pub.orcid.org/v4.0/0000-0002-1825-0097/person", "https://pub.orcid.org/v4.0/status", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.version, func(t *testing.T) {
+			p := NewORCIDProvider(WithORCIDAPIVersion(s.version))
+
+			if v := p.apiURL("0000-0002-1825-0097", "/person"); v != s.expectedAPIURL {
+				t.Fatalf("Expected api url %q, got %q", s.expectedAPIURL, v)
+			}
+
+			if v := p.apiURL("status", ""); v != s.expectedStatusURL {
+				t.Fatalf("Expected status url %q, got %q", s.expectedStatusURL, v)
+			}
+
+			err := p.checkAPIVersion()
+			if hasErr := err != nil; hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedORCIDAPIVersion) {
+				t.Fatalf("Expected ErrUnsupportedORCIDAPIVersion, got %v", err)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserMemberEmailFallback(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		member           bool
+		emailResponse    string
+		emailStatus      int
+		expectedEmail    string
+		expectedRequests []string
+	}{
+		{
+			"non member provider",
+			false,
+			`{"email":[{"email":"limited@example.com","primary":true,"verified":true}]}`,
+			http.StatusOK,
+			"",
+			[]string{"/pub/v3.0/0000-0002-1825-0097/person"},
+		},
+		{
+			"fallback hit",
+			true,
+			`{"email":[{"email":"limited@example.com","primary":true,"verified":true}]}`,
+			http.StatusOK,
+			"limited@example.com",
+			[]string{"/pub/v3.0/0000-0002-1825-0097/person", "/member/v3.0/0000-0002-1825-0097/email"},
+		},
+		{
+			"fallback miss",
+			true,
+			`{"email":[]}`,
+			http.StatusOK,
+			"",
+			[]string{"/pub/v3.0/0000-0002-1825-0097/person", "/member/v3.0/0000-0002-1825-0097/email"},
+		},
+		{
+			"fallback error",
+			true,
+			`{"response-code":403,"developer-message":"insufficient scope"}`,
+			http.StatusForbidden,
+			"",
+			[]string{"/pub/v3.0/0000-0002-1825-0097/person", "/member/v3.0/0000-0002-1825-0097/email"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.URL.Path)
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+
+				if strings.HasSuffix(r.URL.Path, "/email") {
+					w.WriteHeader(s.emailStatus)
+					fmt.Fprint(w, s.emailResponse)
+					return
+				}
+
+				fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"}},"emails":{"email":[]}}`)
+			}))
+			defer srv.Close()
+
+			var opts []ORCIDOption
+			if s.member {
+				opts = append(opts, WithORCIDMember())
+			}
+
+			p := NewORCIDProvider(opts...)
+			p.APIBaseURL = srv.URL + "/pub"
+			p.MemberAPIBaseURL = srv.URL + "/member"
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, user.Email)
+			}
+
+			if !slices.Equal(requests, s.expectedRequests) {
+				t.Fatalf("Expected requests %v, got %v", s.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestORCIDInsufficientScope(t *testing.T) {
+	srv := newTestORCIDServer(t)
+
+	scenarios := []struct {
+		name        string
+		provider    *ORCID
+		scope       any
+		expectError bool
+	}{
+		{"public provider with /authenticate token", srv.provider(), "/authenticate", false},
+		{"member provider without token scope", srv.provider(WithORCIDMember()), nil, false},
+		{"member provider with /authenticate token", srv.provider(WithORCIDMember()), "/authenticate openid", true},
+		{"member provider with /read-limited token", srv.provider(WithORCIDMember()), "/authenticate /read-limited", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			extra := map[string]any{"orcid": testORCIDServeriD}
+			if s.scope != nil {
+				extra["scope"] = s.scope
+			}
+			token := srv.token().WithExtra(extra)
+
+			before := len(srv.requestedPaths())
+
+			_, employmentsErr := s.provider.FetchEmployments(token)
+			_, userErr := s.provider.FetchAuthUser(token)
+
+			for _, err := range []error{employmentsErr, userErr} {
+				hasErr := err != nil
+				if hasErr != s.expectError {
+					t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+				}
+
+				if hasErr && !errors.Is(err, ErrInsufficientScope) {
+					t.Fatalf("Expected ErrInsufficientScope, got %v", err)
+				}
+			}
+
+			if s.expectError && len(srv.requestedPaths()) != before {
+				t.Fatalf("Expected no API requests, got %v", srv.requestedPaths()[before:])
+			}
+		})
+	}
+}
+
+func TestWithORCIDPKCE(t *testing.T) {
+	if !NewORCIDProvider().PKCE() {
+		t.Fatal("Expected PKCE to be enabled by default")
+	}
+
+	if NewORCIDProvider(WithORCIDPKCE(false)).PKCE() {
+		t.Fatal("Expected PKCE to be disabled")
+	}
+
+	if !NewORCIDProvider(WithORCIDPKCE(false), WithORCIDPKCE(true)).PKCE() {
+		t.Fatal("Expected PKCE to be re-enabled")
+	}
+
+	var provider Provider = NewORCIDProvider(WithORCIDSandbox(), WithORCIDPKCE(false))
+	if provider.PKCE() {
+		t.Fatal("Expected the disabled PKCE to be reported by the Provider interface")
+	}
+}
+
+func TestORCIDBuildAuthURLParams(t *testing.T) {
+	p := NewORCIDProvider(
+		WithORCIDAuthParams(map[string]string{"show_login": "true", "lang": "es"}),
+		WithORCIDAuthParams(map[string]string{"family_names": "Carberry"}),
+	)
+	p.SetClientId("test_client_id")
+
+	authURL, err := url.Parse(p.BuildAuthURL("test_state", oauth2.SetAuthURLParam("lang", "fr")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"show_login":    "true",
+		"family_names":  "Carberry",
+		"lang":          "fr", // explicit opts take precedence
+		"state":         "test_state",
+		"client_id":     "test_client_id",
+		"response_type": "code",
+	}
+
+	query := authURL.Query()
+	for key, value := range expected {
+		if v := query.Get(key); v != value {
+			t.Fatalf("Expected %s=%q, got %q (%s)", key, value, v, authURL)
+		}
+	}
+
+	// no extra params
+	plainURL, err := url.Parse(NewORCIDProvider().BuildAuthURL("test_state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plainURL.Query().Has("show_login") {
+		t.Fatalf("Expected no show_login param, got %s", plainURL)
+	}
+}
@@ -0,0 +1,573 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+// ORCIDNameStrategy defines how the user display name is assembled
+// from the ORCID name fields.
+type ORCIDNameStrategy int
+
+const (
+	// ORCIDNameCreditThenGiven uses the credit name ("published name")
+	// and fallbacks to "Given Family" (default).
+	ORCIDNameCreditThenGiven ORCIDNameStrategy = iota
+
+	// ORCIDNameGivenFamily always uses "Given Family" regardless of the credit name.
+	ORCIDNameGivenFamily
+
+	// ORCIDNameFamilyGiven always uses "Family, Given" regardless of the credit name.
+	ORCIDNameFamilyGiven
+)
+
+// resolve returns the display name from the provided name fields.
+//
+// The credit name is used as last resort by all strategies when
+// both the given and family names are empty.
+func (s ORCIDNameStrategy) resolve(givenNames, familyName, creditName string) string {
+	givenNames = strings.TrimSpace(givenNames)
+	familyName = strings.TrimSpace(familyName)
+	creditName = strings.TrimSpace(creditName)
+
+	if s == ORCIDNameCreditThenGiven && creditName != "" {
+		return creditName
+	}
+
+	switch {
+	case givenNames == "" && familyName == "":
+		return creditName
+	case givenNames == "":
+		return familyName
+	case familyName == "":
+		return givenNames
+	case s == ORCIDNameFamilyGiven:
+		return familyName + ", " + givenNames
+	default:
+		return givenNames + " " + familyName
+	}
+}
+
+// ORCIDRawUserSchemaVersion is the current version of the ORCID
+// [AuthUser.RawUser] layout, exported as RawUser "raw_schema_version" key
+// so that the stored RawUser values could be migrated.
+//
+// It is incremented every time a normalized key is changed or removed
+// (new keys could be added without a version change).
+//
+// Version 1:
+//   - the keys of the ORCID /person response are kept as returned by the API
+//     (eg. "name", "addresses", "other-names", "researcher-urls", "external-identifiers")
+//     except "biography", "emails" and "keywords" that are replaced with
+//     their normalized values listed below
+//   - "orcid_uri" (string) - the canonical iD URI
+//   - "given_names", "family_name", "credit_name", "name_visibility" (string)
+//   - "other_names" ([]string)
+//   - "keywords" ([]string, replaces the raw {"keyword":[...]} object)
+//   - "biography" (string, replaces the raw {"content":"...",...} object)
+//   - "country" (string, only if known)
+//   - "emails" ([]ORCIDEmail, replaces the raw {"email":[...]} object)
+//   - "researcher_urls" ([]ORCIDResearcherURL)
+//   - "external_identifiers" ([]ORCIDExternalIdentifier)
+//   - "last_modified", "created" (types.DateTime, only if known; created only with [ORCID.UseRecord])
+//   - "locale" (string, only with [ORCID.UseRecord])
+//   - "deprecated_orcid" (string, only with [ORCID.FollowPrimaryRecord])
+//
+// With [ORCID.SkipEmail] and an id_token, the RawUser contains the
+// id_token claims, "orcid_uri" and "raw_schema_version" instead.
+const ORCIDRawUserSchemaVersion int = 1
+
+// parsePerson parses the provided ORCID /person response and returns
+// its normalized RawUser representation together with the resolved
+// user display name and email.
+func (p *ORCID) parsePerson(data []byte, iD string) (map[string]any, string, string, error) {
+	if p.StrictDecoding {
+		if err := decodeORCIDStrict(data, &orcidStrictPerson{}); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, "", "", err
+	}
+	if rawUser == nil {
+		rawUser = map[string]any{} // null person
+	}
+	rawUser["orcid_uri"] = p.iDURI(iD)
+	rawUser["raw_schema_version"] = ORCIDRawUserSchemaVersion
+
+	extracted := struct {
+		LastModifiedDate struct {
+			Value int64 `json:"value"` // epoch millis
+		} `json:"last-modified-date"` // null for new records without public data
+		orcidJSONPersonalDetails
+		Emails struct {
+			Email []ORCIDEmail `json:"email"`
+		} `json:"emails"`
+		Addresses struct {
+			Address []struct {
+				Country struct {
+					Value string `json:"value"`
+				} `json:"country"`
+				Primary bool `json:"primary"`
+			} `json:"address"`
+		} `json:"addresses"`
+		Keywords struct {
+			Keyword []struct {
+				Content string `json:"content"`
+			} `json:"keyword"`
+		} `json:"keywords"`
+		ResearcherURLs struct {
+			ResearcherURL []struct {
+				URLName string `json:"url-name"`
+				URL     struct {
+					Value string `json:"value"`
+				} `json:"url"`
+				Visibility string `json:"visibility"`
+			} `json:"researcher-url"`
+		} `json:"researcher-urls"`
+		ExternalIdentifiers struct {
+			ExternalIdentifier []struct {
+				Source *orcidJSONSource `json:"source"`
+				Type   string           `json:"external-id-type"`
+				Value  string           `json:"external-id-value"`
+				URL    struct {
+					Value string `json:"value"`
+				} `json:"external-id-url"` // could be null
+				Visibility string `json:"visibility"`
+			} `json:"external-identifier"`
+		} `json:"external-identifiers"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, "", "", err
+	}
+
+	details := extracted.toPersonalDetails(p.NameStrategy)
+
+	// the name components are always set (empty string if private or missing)
+	rawUser["given_names"] = details.GivenNames
+	rawUser["family_name"] = details.FamilyName
+	rawUser["credit_name"] = details.CreditName
+	rawUser["name_visibility"] = details.NameVisibility
+
+	if extracted.LastModifiedDate.Value > 0 {
+		lastModified, err := types.ParseDateTime(time.UnixMilli(extracted.LastModifiedDate.Value))
+		if err != nil {
+			return nil, "", "", err
+		}
+		rawUser["last_modified"] = lastModified
+	}
+
+	rawUser["biography"] = details.Biography
+
+	// ISO 3166 alpha-2 country code of the primary (or first) address
+	var country string
+	for _, address := range extracted.Addresses.Address {
+		if address.Country.Value == "" {
+			continue
+		}
+		if country == "" || address.Primary {
+			country = address.Country.Value
+		}
+		if address.Primary {
+			break
+		}
+	}
+	if country != "" {
+		rawUser["country"] = country
+	}
+
+	keywords := make([]string, 0, len(extracted.Keywords.Keyword))
+	for _, keyword := range extracted.Keywords.Keyword {
+		keywords = appendUniqueFold(keywords, keyword.Content)
+	}
+	rawUser["keywords"] = keywords
+
+	researcherURLs := make([]ORCIDResearcherURL, 0, len(extracted.ResearcherURLs.ResearcherURL))
+	for _, researcherURL := range extracted.ResearcherURLs.ResearcherURL {
+		if researcherURL.URL.Value == "" {
+			continue
+		}
+		researcherURLs = append(researcherURLs, ORCIDResearcherURL{
+			Name:       researcherURL.URLName,
+			URL:        researcherURL.URL.Value,
+			Visibility: normalizeORCIDVisibility(researcherURL.Visibility),
+		})
+	}
+	rawUser["researcher_urls"] = researcherURLs
+
+	rawUser["other_names"] = details.OtherNames
+
+	externalIdentifiers := make([]ORCIDExternalIdentifier, 0, len(extracted.ExternalIdentifiers.ExternalIdentifier))
+	for _, externalId := range extracted.ExternalIdentifiers.ExternalIdentifier {
+		if externalId.Value == "" {
+			continue
+		}
+		externalIdentifiers = append(externalIdentifiers, ORCIDExternalIdentifier{
+			Source:     externalId.Source.toSource(),
+			Type:       externalId.Type,
+			Value:      externalId.Value,
+			URL:        externalId.URL.Value,
+			Visibility: normalizeORCIDVisibility(externalId.Visibility),
+		})
+	}
+	rawUser["external_identifiers"] = externalIdentifiers
+
+	email := selectORCIDEmail(extracted.Emails.Email, p.StrictEmail)
+
+	emails := make([]ORCIDEmail, 0, len(extracted.Emails.Email))
+	for _, e := range extracted.Emails.Email {
+		if e.Email != "" {
+			e.Visibility = normalizeORCIDVisibility(e.Visibility)
+			emails = append(emails, e)
+		}
+	}
+	rawUser["emails"] = emails
+
+	return rawUser, details.Name, email, nil
+}
+
+// ORCIDPersonalDetails defines the ORCID record names and biography.
+type ORCIDPersonalDetails struct {
+	// Name is the display name resolved with the provider NameStrategy.
+	Name string `json:"name"`
+
+	GivenNames     string   `json:"given_names"`
+	FamilyName     string   `json:"family_name"`
+	CreditName     string   `json:"credit_name"`
+	NameVisibility string   `json:"name_visibility,omitempty"`
+	Biography      string   `json:"biography"`
+	OtherNames     []string `json:"other_names"`
+}
+
+// FetchPersonalDetails fetches only the /personal-details section
+// of the token ORCID record (aka. the names and biography).
+//
+// It is a lighter alternative to [ORCID.FetchAuthUser] for profile displays.
+func (p *ORCID) FetchPersonalDetails(token *oauth2.Token) (*ORCIDPersonalDetails, error) {
+	return p.FetchPersonalDetailsContext(p.ctx, token)
+}
+
+// FetchPersonalDetailsContext is similar to [ORCID.FetchPersonalDetails] but uses the specified ctx for the requests.
+func (p *ORCID) FetchPersonalDetailsContext(ctx context.Context, token *oauth2.Token) (*ORCIDPersonalDetails, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/personal-details"))
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := orcidJSONPersonalDetails{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	return extracted.toPersonalDetails(p.NameStrategy), nil
+}
+
+// orcidJSONPersonalDetails defines the ORCID API names and biography JSON representation
+// shared by the /person and /personal-details responses.
+type orcidJSONPersonalDetails struct {
+	Name struct {
+		Visibility string `json:"visibility"`
+		GivenNames struct {
+			Value string `json:"value"`
+		} `json:"given-names"`
+		FamilyName struct {
+			Value string `json:"value"`
+		} `json:"family-name"`
+		CreditName struct {
+			Value string `json:"value"`
+		} `json:"credit-name"`
+	} `json:"name"`
+	Biography struct {
+		Content string `json:"content"`
+	} `json:"biography"` // null if missing or private
+	OtherNames struct {
+		OtherName []struct {
+			Content string `json:"content"`
+		} `json:"other-name"`
+	} `json:"other-names"`
+}
+
+func (d *orcidJSONPersonalDetails) toPersonalDetails(strategy ORCIDNameStrategy) *ORCIDPersonalDetails {
+	details := &ORCIDPersonalDetails{
+		Name:           strategy.resolve(d.Name.GivenNames.Value, d.Name.FamilyName.Value, d.Name.CreditName.Value),
+		GivenNames:     strings.TrimSpace(d.Name.GivenNames.Value),
+		FamilyName:     strings.TrimSpace(d.Name.FamilyName.Value),
+		CreditName:     strings.TrimSpace(d.Name.CreditName.Value),
+		NameVisibility: normalizeORCIDVisibility(d.Name.Visibility),
+		Biography:      d.Biography.Content,
+		OtherNames:     make([]string, 0, len(d.OtherNames.OtherName)),
+	}
+
+	for _, otherName := range d.OtherNames.OtherName {
+		details.OtherNames = appendUniqueFold(details.OtherNames, otherName.Content)
+	}
+
+	return details
+}
+
+// ErrORCIDEmailsUnavailable is returned by [ORCID.EmailMatchesRecord]
+// when the record emails are private or not accessible with the token.
+var ErrORCIDEmailsUnavailable = errors.New("the ORCID record emails are private or unavailable")
+
+// EmailMatchesRecord reports whether the specified email is one of the
+// verified emails of the token ORCID record (case-insensitive).
+//
+// It returns [ErrORCIDEmailsUnavailable] instead of a false negative
+// when the record has no accessible emails (eg. all of them are private).
+func (p *ORCID) EmailMatchesRecord(token *oauth2.Token, email string) (bool, error) {
+	return p.EmailMatchesRecordContext(p.ctx, token, email)
+}
+
+// EmailMatchesRecordContext is similar to [ORCID.EmailMatchesRecord] but uses the specified ctx for the requests.
+func (p *ORCID) EmailMatchesRecordContext(ctx context.Context, token *oauth2.Token, email string) (bool, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return false, errors.New("missing email to match")
+	}
+
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return false, err
+	}
+
+	emails, err := p.fetchEmails(ctx, token, p.emailURL(iD))
+	if err != nil {
+		var apiErr *ORCIDAPIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusForbidden {
+			return false, fmt.Errorf("%w: %w", ErrORCIDEmailsUnavailable, err)
+		}
+		return false, err
+	}
+
+	if len(emails) == 0 {
+		return false, ErrORCIDEmailsUnavailable
+	}
+
+	for _, e := range emails {
+		if e.Verified && strings.EqualFold(e.Email, email) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// FetchEmail fetches only the /email section of the token ORCID record
+// and returns the selected user email (see [ORCID.StrictEmail]) together with
+// all available record emails.
+//
+// It is a lighter alternative to [ORCID.FetchAuthUser] for flows that
+// need only the email (eg. account linking).
+func (p *ORCID) FetchEmail(token *oauth2.Token) (string, []ORCIDEmail, error) {
+	return p.FetchEmailContext(p.ctx, token)
+}
+
+// FetchEmailContext is similar to [ORCID.FetchEmail] but uses the specified ctx for the requests.
+func (p *ORCID) FetchEmailContext(ctx context.Context, token *oauth2.Token) (string, []ORCIDEmail, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	emails, err := p.fetchEmails(ctx, token, p.emailURL(iD))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return selectORCIDEmail(emails, p.StrictEmail), emails, nil
+}
+
+// emailURL returns the /email section url of the specified iD
+// (the member API is preferred when available because it also returns the limited emails).
+func (p *ORCID) emailURL(iD string) string {
+	if p.member && p.MemberAPIBaseURL != "" {
+		return p.memberAPIURL(iD, "/email")
+	}
+
+	return p.apiURL(iD, "/email")
+}
+
+// fetchMemberEmails fetches the user emails from the member API /email endpoint.
+//
+// The fallback is best-effort and any fetch or decode error results in nil emails
+// (eg. when the token doesn't have the "/read-limited" scope).
+func (p *ORCID) fetchMemberEmails(ctx context.Context, token *oauth2.Token, iD string) []ORCIDEmail {
+	emails, err := p.fetchEmails(ctx, token, p.memberAPIURL(iD, "/email"))
+	if err != nil {
+		return nil
+	}
+
+	return emails
+}
+
+// fetchEmails fetches and returns the non-empty emails from the specified /email section url.
+func (p *ORCID) fetchEmails(ctx context.Context, token *oauth2.Token, url string) ([]ORCIDEmail, error) {
+	data, err := p.fetchJSON(ctx, token, url)
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Email []ORCIDEmail `json:"email"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	emails := make([]ORCIDEmail, 0, len(extracted.Email))
+	for _, e := range extracted.Email {
+		if e.Email != "" {
+			e.Visibility = normalizeORCIDVisibility(e.Visibility)
+			emails = append(emails, e)
+		}
+	}
+
+	return emails, nil
+}
+
+// ORCIDPerson defines the typed representation of the ORCID person
+// data parsed by this package (the same data is also available as
+// the [AuthUser.RawUser] keys returned by [ORCID.FetchAuthUser]).
+type ORCIDPerson struct {
+	ORCID string `json:"orcid"`
+	URI   string `json:"orcid_uri"`
+
+	// Name and Email are the resolved display name and the selected email
+	// (see [ORCID.NameStrategy] and [ORCID.StrictEmail]).
+	Name  string `json:"name"`
+	Email string `json:"email"`
+
+	GivenNames          string                    `json:"given_names"`
+	FamilyName          string                    `json:"family_name"`
+	CreditName          string                    `json:"credit_name"`
+	NameVisibility      string                    `json:"name_visibility,omitempty"`
+	OtherNames          []string                  `json:"other_names"`
+	Biography           string                    `json:"biography"`
+	Emails              []ORCIDEmail              `json:"emails"`
+	Keywords            []string                  `json:"keywords"`
+	ResearcherURLs      []ORCIDResearcherURL      `json:"researcher_urls"`
+	Country             string                    `json:"country,omitempty"`
+	ExternalIdentifiers []ORCIDExternalIdentifier `json:"external_identifiers"`
+
+	// Locale is the user preferred locale (available only with [ORCID.UseRecord]).
+	Locale string `json:"locale,omitempty"`
+
+	LastModified types.DateTime `json:"last_modified"`
+
+	// Created is the record creation date (available only with [ORCID.UseRecord]).
+	Created types.DateTime `json:"created"`
+}
+
+// FetchPerson fetches and returns the typed person data of the token ORCID user.
+//
+// It applies the same provider settings (and person cache) as [ORCID.FetchAuthUser].
+func (p *ORCID) FetchPerson(token *oauth2.Token) (*ORCIDPerson, error) {
+	return p.FetchPersonContext(p.ctx, token)
+}
+
+// FetchPersonContext is similar to [ORCID.FetchPerson] but uses the specified ctx for the requests.
+func (p *ORCID) FetchPersonContext(ctx context.Context, token *oauth2.Token) (*ORCIDPerson, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := p.fetchPerson(ctx, token, iD)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.person(iD), nil
+}
+
+// ORCIDPersonISNI returns the normalized ISNI (International Standard Name Identifier)
+// of the person external identifiers, aka. its 16 characters without the group
+// separators (eg. "0000 0001 2146 438X" -> "000000012146438X").
+//
+// It returns an empty string if the person doesn't have a valid ISNI.
+func ORCIDPersonISNI(person *ORCIDPerson) string {
+	if person == nil {
+		return ""
+	}
+
+	for _, id := range person.ExternalIdentifiers {
+		if !strings.EqualFold(id.Type, "ISNI") {
+			continue
+		}
+
+		if isni := normalizeORCIDISNI(id.Value); isni != "" {
+			return isni
+		}
+	}
+
+	return ""
+}
+
+// normalizeORCIDISNI strips the group separators and the isni.org url prefix
+// (if any) from the specified ISNI and validates its check character.
+//
+// It returns an empty string if the value is not a valid ISNI.
+func normalizeORCIDISNI(value string) string {
+	value = strings.ToUpper(strings.TrimRight(strings.TrimSpace(value), "/"))
+
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		value = value[i+1:]
+	}
+
+	isni := strings.NewReplacer(" ", "", "-", "", "\u00a0", "").Replace(value)
+	if len(isni) != 16 {
+		return ""
+	}
+
+	// ORCID iDs are a subset of the ISNI block and therefore
+	// they share the same ISO 7064 MOD 11-2 check character
+	if validateORCIDiD(isni[0:4]+"-"+isni[4:8]+"-"+isni[8:12]+"-"+isni[12:16]) != nil {
+		return ""
+	}
+
+	return isni
+}
+
+// person converts the parsed person RawUser map into ORCIDPerson.
+func (pp *orcidParsedPerson) person(iD string) *ORCIDPerson {
+	person := &ORCIDPerson{
+		ORCID: iD,
+		Name:  pp.name,
+		Email: pp.email,
+	}
+
+	// the values are stored with their concrete types by parsePerson
+	// and the type assertions fail only for missing keys
+	person.URI, _ = pp.rawUser["orcid_uri"].(string)
+	person.GivenNames, _ = pp.rawUser["given_names"].(string)
+	person.FamilyName, _ = pp.rawUser["family_name"].(string)
+	person.CreditName, _ = pp.rawUser["credit_name"].(string)
+	person.NameVisibility, _ = pp.rawUser["name_visibility"].(string)
+	person.OtherNames, _ = pp.rawUser["other_names"].([]string)
+	person.Biography, _ = pp.rawUser["biography"].(string)
+	person.Emails, _ = pp.rawUser["emails"].([]ORCIDEmail)
+	person.Keywords, _ = pp.rawUser["keywords"].([]string)
+	person.ResearcherURLs, _ = pp.rawUser["researcher_urls"].([]ORCIDResearcherURL)
+	person.Country, _ = pp.rawUser["country"].(string)
+	person.ExternalIdentifiers, _ = pp.rawUser["external_identifiers"].([]ORCIDExternalIdentifier)
+	person.Locale, _ = pp.rawUser["locale"].(string)
+	person.LastModified, _ = pp.rawUser["last_modified"].(types.DateTime)
+	person.Created, _ = pp.rawUser["created"].(types.DateTime)
+
+	return person
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// ORCID profile sections fetched by [ORCID.FetchProfile].
+const (
+	ORCIDSectionPerson      string = "person"
+	ORCIDSectionEmployments string = "employments"
+	ORCIDSectionWorks       string = "works"
+)
+
+var orcidProfileSections = []string{
+	ORCIDSectionPerson,
+	ORCIDSectionEmployments,
+	ORCIDSectionWorks,
+}
+
+// ORCIDProfile defines the aggregated ORCID person, employments and works data.
+type ORCIDProfile struct {
+	// Person is the normalized person data in the same format as AuthUser.RawUser.
+	Person map[string]any `json:"person"`
+
+	// Name is the resolved person display name.
+	Name string `json:"name"`
+
+	// Email is the resolved person email (see [ORCID.StrictEmail]).
+	Email string `json:"email"`
+
+	// Emails is the list of all non-private person emails.
+	Emails []ORCIDEmail `json:"emails"`
+
+	Employments []ORCIDAffiliation `json:"employments"`
+	Works       []ORCIDWork        `json:"works"`
+
+	// Errors holds the fetch errors of the failed profile sections
+	// keyed by the section name (eg. [ORCIDSectionWorks]).
+	Errors map[string]error `json:"-"`
+}
+
+// IsPartial reports whether some, but not all, of the profile sections failed.
+func (p *ORCIDProfile) IsPartial() bool {
+	return len(p.Errors) > 0 && len(p.Errors) < len(orcidProfileSections)
+}
+
+// Err returns the joined section errors (or nil if all sections were fetched successfully).
+func (p *ORCIDProfile) Err() error {
+	if len(p.Errors) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0, len(p.Errors))
+	for _, section := range slices.Sorted(maps.Keys(p.Errors)) {
+		errs = append(errs, p.Errors[section])
+	}
+
+	return errors.Join(errs...)
+}
+
+// FetchProfile concurrently fetches the person, employments and works
+// sections of the authenticated ORCID user.
+//
+// A failing section doesn't fail the entire fetch. Instead the section
+// error is recorded in the returned profile Errors and the remaining
+// sections are still populated.
+// An error is returned only if the token doesn't have a valid ORCID iD.
+func (p *ORCID) FetchProfile(token *oauth2.Token) (*ORCIDProfile, error) {
+	return p.FetchProfileContext(p.ctx, token)
+}
+
+// FetchProfileContext is similar to [ORCID.FetchProfile] but uses the specified ctx for the requests.
+func (p *ORCID) FetchProfileContext(ctx context.Context, token *oauth2.Token) (*ORCIDProfile, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &ORCIDProfile{
+		Errors: map[string]error{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	fetch := func(section string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := fn(); err != nil {
+				mu.Lock()
+				profile.Errors[section] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// each goroutine sets only its own profile fields
+	fetch(ORCIDSectionPerson, func() error {
+		person, err := p.fetchPerson(ctx, token, iD)
+		if err != nil {
+			return err
+		}
+
+		profile.Person = person.rawUser
+		profile.Name = person.name
+		profile.Email = person.email
+		profile.Emails, _ = person.rawUser["emails"].([]ORCIDEmail)
+
+		return nil
+	})
+
+	fetch(ORCIDSectionEmployments, func() error {
+		var err error
+		profile.Employments, err = p.FetchEmploymentsContext(ctx, token)
+		return err
+	})
+
+	fetch(ORCIDSectionWorks, func() error {
+		var err error
+		profile.Works, err = p.FetchWorksContext(ctx, token)
+		return err
+	})
+
+	wg.Wait()
+
+	return profile, nil
+}
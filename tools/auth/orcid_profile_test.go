@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestORCIDFetchProfile(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		failingSection string
+	}{
+		{"all sections", ""},
+		{"failing works", "/works"},
+		{"failing person", "/person"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if s.failingSection != "" && strings.HasSuffix(r.URL.Path, s.failingSection) {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"response-code":400,"developer-message":"test error","error-code":9000}`)
+					return
+				}
+
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/person"):
+					fmt.Fprint(w, testORCIDPersonJSON)
+				case strings.HasSuffix(r.URL.Path, "/employments"):
+					fmt.Fprint(w, testORCIDEmploymentsJSON)
+				case strings.HasSuffix(r.URL.Path, "/works"):
+					fmt.Fprint(w, testORCIDWorksJSON)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			profile, err := p.FetchProfile(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if s.failingSection == "" {
+				if len(profile.Errors) != 0 || profile.Err() != nil {
+					t.Fatalf("Expected no section errors, got %v", profile.Errors)
+				}
+			} else {
+				section := strings.TrimPrefix(s.failingSection, "/")
+
+				var apiErr *ORCIDAPIError
+				if !errors.As(profile.Errors[section], &apiErr) || apiErr.ErrorCode != 9000 {
+					t.Fatalf("Expected %s section ORCIDAPIError, got %v", section, profile.Errors)
+				}
+
+				if len(profile.Errors) != 1 {
+					t.Fatalf("Expected only 1 section error, got %v", profile.Errors)
+				}
+
+				if !errors.Is(profile.Err(), apiErr) {
+					t.Fatalf("Expected Err() to contain the section error, got %v", profile.Err())
+				}
+			}
+
+			if s.failingSection != "/person" {
+				if profile.Name != "Josiah S. Carberry" || profile.Email != "josiah@example.com" {
+					t.Fatalf("Expected the person name and email, got %q and %q", profile.Name, profile.Email)
+				}
+
+				if profile.Person["orcid_uri"] != "https://orcid.org/0000-0002-1825-0097" {
+					t.Fatalf("Expected the person raw data, got %v", profile.Person)
+				}
+			} else if profile.Person != nil {
+				t.Fatalf("Expected nil person, got %v", profile.Person)
+			}
+
+			if len(profile.Employments) != 2 {
+				t.Fatalf("Expected 2 employments, got %v", profile.Employments)
+			}
+
+			expectedWorks := 2
+			if s.failingSection == "/works" {
+				expectedWorks = 0
+			}
+			if len(profile.Works) != expectedWorks {
+				t.Fatalf("Expected %d works, got %v", expectedWorks, profile.Works)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchProfileInvalidToken(t *testing.T) {
+	_, err := NewORCIDProvider().FetchProfile(&oauth2.Token{AccessToken: "test"})
+	if !errors.Is(err, ErrMissingORCIDiD) {
+		t.Fatalf("Expected ErrMissingORCIDiD, got %v", err)
+	}
+}
+
+func TestORCIDFetchProfilePartialFailure(t *testing.T) {
+	srv := newTestORCIDServer(t)
+	srv.setResponse("/v3.0/"+testORCIDServeriD+"/works", http.StatusInternalServerError, `{"response-code":500,"developer-message":"Internal Server Error"}`)
+
+	p := srv.provider()
+
+	profile, err := p.FetchProfile(srv.token())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !profile.IsPartial() {
+		t.Fatalf("Expected partial profile, got errors %v", profile.Errors)
+	}
+
+	var apiErr *ORCIDAPIError
+	if !errors.As(profile.Errors[ORCIDSectionWorks], &apiErr) || apiErr.Status != http.StatusInternalServerError {
+		t.Fatalf("Expected works 500 error, got %v", profile.Errors)
+	}
+
+	// the 5xx responses are retried
+	var worksRequests int
+	for _, path := range srv.requestedPaths() {
+		if strings.HasSuffix(path, "/works") {
+			worksRequests++
+		}
+	}
+	if worksRequests != p.MaxRetries+1 {
+		t.Fatalf("Expected %d works requests, got %d", p.MaxRetries+1, worksRequests)
+	}
+
+	if profile.Email != "josiah@example.com" || len(profile.Emails) != 2 {
+		t.Fatalf("Expected the person emails, got %q and %v", profile.Email, profile.Emails)
+	}
+
+	if len(profile.Employments) != 2 {
+		t.Fatalf("Expected 2 employments, got %v", profile.Employments)
+	}
+
+	if profile.Works != nil {
+		t.Fatalf("Expected nil works, got %v", profile.Works)
+	}
+
+	// all sections failing
+	for _, section := range []string{"/person", "/employments"} {
+		srv.setResponse("/v3.0/"+testORCIDServeriD+section, http.StatusInternalServerError, `{"response-code":500}`)
+	}
+
+	profile, err = p.FetchProfile(srv.token())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.IsPartial() || len(profile.Errors) != 3 {
+		t.Fatalf("Expected all sections to fail, got %v", profile.Errors)
+	}
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"golang.org/x/oauth2"
+)
+
+// ORCIDRecord defines the normalized ORCID full record data.
+type ORCIDRecord struct {
+	// Person is the normalized person data in the same format as AuthUser.RawUser.
+	Person map[string]any `json:"person"`
+
+	// Name is the resolved person display name.
+	Name string `json:"name"`
+
+	// Email is the resolved person email (see [ORCID.StrictEmail]).
+	Email string `json:"email"`
+
+	Employments []ORCIDAffiliation `json:"employments"`
+	Educations  []ORCIDAffiliation `json:"educations"`
+	Works       []ORCIDWork        `json:"works"`
+	Fundings    []ORCIDFunding     `json:"fundings"`
+}
+
+// FetchRecord returns the full record of the authenticated ORCID user
+// (person and activities summaries) with a single /record request.
+func (p *ORCID) FetchRecord(token *oauth2.Token) (*ORCIDRecord, error) {
+	return p.FetchRecordContext(p.ctx, token)
+}
+
+// FetchRecordContext is similar to [ORCID.FetchRecord] but uses the specified ctx for the requests.
+func (p *ORCID) FetchRecordContext(ctx context.Context, token *oauth2.Token) (*ORCIDRecord, error) {
+	iD, err := p.readTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL(iD, "/record"))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseRecord(data, iD)
+}
+
+// parseRecord parses the provided ORCID /record response using the
+// same parsers as the section specific fetch methods.
+func (p *ORCID) parseRecord(data []byte, iD string) (*ORCIDRecord, error) {
+	extracted := struct {
+		Person      json.RawMessage        `json:"person"`
+		Preferences orcidRecordPreferences `json:"preferences"`
+		History     orcidRecordHistory     `json:"history"`
+		Activities  struct {
+			Employments json.RawMessage `json:"employments"`
+			Educations  json.RawMessage `json:"educations"`
+			Works       json.RawMessage `json:"works"`
+			Fundings    json.RawMessage `json:"fundings"`
+		} `json:"activities-summary"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	record := &ORCIDRecord{}
+
+	var err error
+
+	record.Person, record.Name, record.Email, err = p.parsePerson(orcidRawOrNull(extracted.Person), iD)
+	if err != nil {
+		return nil, err
+	}
+
+	if extracted.Preferences.Locale != "" {
+		record.Person["locale"] = extracted.Preferences.Locale
+	}
+
+	if err := setORCIDCreated(record.Person, extracted.History.SubmissionDate.Value); err != nil {
+		return nil, err
+	}
+
+	record.Employments, err = parseORCIDAffiliations(orcidRawOrNull(extracted.Activities.Employments), "employment-summary")
+	if err != nil {
+		return nil, err
+	}
+
+	record.Educations, err = parseORCIDAffiliations(orcidRawOrNull(extracted.Activities.Educations), "education-summary")
+	if err != nil {
+		return nil, err
+	}
+
+	record.Works, err = parseORCIDWorks(orcidRawOrNull(extracted.Activities.Works))
+	if err != nil {
+		return nil, err
+	}
+
+	record.Fundings, err = parseORCIDFundings(orcidRawOrNull(extracted.Activities.Fundings))
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// orcidRecordPreferences defines the record "preferences" block
+// (null if missing).
+type orcidRecordPreferences struct {
+	// Locale is the user preferred display language (eg. "en", "zh_CN").
+	Locale string `json:"locale"`
+}
+
+// orcidRecordHistory defines the record "history" block
+// (null if missing, eg. in older API versions or mocked responses).
+type orcidRecordHistory struct {
+	// SubmissionDate is the record creation time.
+	SubmissionDate struct {
+		Value int64 `json:"value"` // epoch millis
+	} `json:"submission-date"`
+}
+
+// setORCIDCreated sets the record creation date as rawUser "created" key
+// (brand-new records could be used as a spam signal).
+//
+// The key is not set if the creation date is unknown (aka. 0).
+func setORCIDCreated(rawUser map[string]any, millis int64) error {
+	if millis <= 0 {
+		return nil
+	}
+
+	created, err := types.ParseDateTime(time.UnixMilli(millis))
+	if err != nil {
+		return err
+	}
+
+	rawUser["created"] = created
+
+	return nil
+}
+
+// orcidRawOrNull returns "null" JSON if the provided raw message is empty
+// (eg. when a record section is missing) so that it can be safely unmarshalized.
+func orcidRawOrNull(raw json.RawMessage) []byte {
+	if len(raw) == 0 {
+		return []byte("null")
+	}
+
+	return raw
+}
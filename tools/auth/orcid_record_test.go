@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+// testORCIDRecordJSON is a full /record response composed from the section fixtures.
+var testORCIDRecordJSON = fmt.Sprintf(`{
+	"orcid-identifier": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"},
+	"preferences": {"locale": "en"},
+	"history": {
+		"creation-method": "MEMBER_REFERRED",
+		"submission-date": {"value": 1460757617078},
+		"last-modified-date": {"value": 1704067200000},
+		"claimed": true,
+		"source": null,
+		"deactivation-date": null,
+		"verified-email": true,
+		"verified-primary-email": true
+	},
+	"person": %s,
+	"activities-summary": {
+		"last-modified-date": {"value": 1704067200000},
+		"distinctions": {"affiliation-group": [], "path": "/0000-0002-1825-0097/distinctions"},
+		"educations": %s,
+		"employments": %s,
+		"fundings": %s,
+		"invited-positions": {"affiliation-group": [], "path": "/0000-0002-1825-0097/invited-positions"},
+		"memberships": {"affiliation-group": [], "path": "/0000-0002-1825-0097/memberships"},
+		"peer-reviews": {"group": [], "path": "/0000-0002-1825-0097/peer-reviews"},
+		"qualifications": {"affiliation-group": [], "path": "/0000-0002-1825-0097/qualifications"},
+		"research-resources": {"group": [], "path": "/0000-0002-1825-0097/research-resources"},
+		"services": {"affiliation-group": [], "path": "/0000-0002-1825-0097/services"},
+		"works": %s,
+		"path": "/0000-0002-1825-0097/activities"
+	},
+	"path": "/0000-0002-1825-0097"
+}`,
+	testORCIDPersonJSON,
+	testORCIDEducationsJSON,
+	testORCIDEmploymentsJSON,
+	testORCIDFundingsJSON,
+	testORCIDWorksJSON,
+)
+
+func TestORCIDFetchRecord(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/record", testORCIDRecordJSON)
+	defer cleanup()
+
+	record, err := p.FetchRecord(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if record.Name != "Josiah S. Carberry" {
+		t.Fatalf("Expected name %q, got %q", "Josiah S. Carberry", record.Name)
+	}
+
+	if record.Email != "josiah@example.com" {
+		t.Fatalf("Expected email %q, got %q", "josiah@example.com", record.Email)
+	}
+
+	if record.Person["country"] != "US" {
+		t.Fatalf("Expected person country %q, got %#v", "US", record.Person["country"])
+	}
+
+	if record.Person["orcid_uri"] != "https://orcid.org/0000-0002-1825-0097" {
+		t.Fatalf("Expected person orcid_uri, got %#v", record.Person["orcid_uri"])
+	}
+
+	if record.Person["locale"] != "en" {
+		t.Fatalf("Expected person locale %q, got %#v", "en", record.Person["locale"])
+	}
+
+	if created, _ := record.Person["created"].(types.DateTime); created.Time().UnixMilli() != 1460757617078 {
+		t.Fatalf("Expected person created %d, got %#v", 1460757617078, record.Person["created"])
+	}
+
+	// compare the activities with the section specific parsers results
+	expectedEmployments, _ := parseORCIDAffiliations([]byte(testORCIDEmploymentsJSON), "employment-summary")
+	if !reflect.DeepEqual(record.Employments, expectedEmployments) {
+		t.Fatalf("Expected employments\n%#v\ngot\n%#v", expectedEmployments, record.Employments)
+	}
+
+	expectedEducations, _ := parseORCIDAffiliations([]byte(testORCIDEducationsJSON), "education-summary")
+	if !reflect.DeepEqual(record.Educations, expectedEducations) {
+		t.Fatalf("Expected educations\n%#v\ngot\n%#v", expectedEducations, record.Educations)
+	}
+
+	expectedWorks, _ := parseORCIDWorks([]byte(testORCIDWorksJSON))
+	if !reflect.DeepEqual(record.Works, expectedWorks) {
+		t.Fatalf("Expected works\n%#v\ngot\n%#v", expectedWorks, record.Works)
+	}
+
+	expectedFundings, _ := parseORCIDFundings([]byte(testORCIDFundingsJSON))
+	if !reflect.DeepEqual(record.Fundings, expectedFundings) {
+		t.Fatalf("Expected fundings\n%#v\ngot\n%#v", expectedFundings, record.Fundings)
+	}
+
+	if len(record.Employments) != 2 || len(record.Educations) != 2 || len(record.Works) != 2 || len(record.Fundings) != 2 {
+		t.Fatalf("Expected 2 items per activities section, got %#v", record)
+	}
+}
+
+func TestORCIDFetchRecordMissingSections(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/record", `{"person":null}`)
+	defer cleanup()
+
+	record, err := p.FetchRecord(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if record.Name != "" || record.Email != "" {
+		t.Fatalf("Expected empty name and email, got %q and %q", record.Name, record.Email)
+	}
+
+	if len(record.Employments) != 0 || len(record.Educations) != 0 || len(record.Works) != 0 || len(record.Fundings) != 0 {
+		t.Fatalf("Expected empty activities sections, got %#v", record)
+	}
+
+	if _, ok := record.Person["locale"]; ok {
+		t.Fatalf("Expected no locale for missing preferences, got %v", record.Person["locale"])
+	}
+
+	if _, ok := record.Person["created"]; ok {
+		t.Fatalf("Expected no created for missing history, got %v", record.Person["created"])
+	}
+}
+
+func TestORCIDFetchAuthUserUseRecord(t *testing.T) {
+	p, token, cleanup := testORCIDSectionProvider(t, "/v3.0/0000-0002-1825-0097/record", testORCIDRecordJSON)
+	defer cleanup()
+
+	p.UseRecord = true
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	if user.Name != "Josiah S. Carberry" {
+		t.Fatalf("Expected name %q, got %q", "Josiah S. Carberry", user.Name)
+	}
+
+	if user.Email != "josiah@example.com" {
+		t.Fatalf("Expected email %q, got %q", "josiah@example.com", user.Email)
+	}
+
+	if v := user.RawUser["locale"]; v != "en" {
+		t.Fatalf("Expected locale %q, got %v", "en", v)
+	}
+
+	created, ok := user.RawUser["created"].(types.DateTime)
+	if !ok || !created.Time().Equal(time.UnixMilli(1460757617078)) {
+		t.Fatalf("Expected created %v, got %v", time.UnixMilli(1460757617078), user.RawUser["created"])
+	}
+}
+
+func TestORCIDFetchAcceptLanguage(t *testing.T) {
+	var acceptLanguage string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptLanguage = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, testORCIDRecordJSON)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider(WithORCIDAcceptLanguage("es"), WithORCIDRecord())
+	p.APIBaseURL = srv.URL
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	if _, err := p.FetchAuthUser(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if acceptLanguage != "es" {
+		t.Fatalf("Expected Accept-Language %q, got %q", "es", acceptLanguage)
+	}
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SearchByEmail searches the ORCID registry for the iDs of the users
+// with the specified public email address.
+//
+// The search uses the app "/read-public" client credentials token
+// (see [ORCID.ClientCredentialsToken]) and returns an empty slice if there are no matches.
+//
+// API reference: https://info.orcid.org/documentation/api-tutorials/api-tutorial-searching-the-orcid-registry/
+func (p *ORCID) SearchByEmail(ctx context.Context, email string) ([]string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, errors.New("missing ORCID search email")
+	}
+
+	token, err := p.ClientCredentialsToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.searchURL("search", url.Values{
+		"q": {"email:" + orcidLuceneEscape(email)},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Result []struct {
+			Identifier struct {
+				Path string `json:"path"`
+			} `json:"orcid-identifier"`
+		} `json:"result"` // null if there are no matches
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	iDs := make([]string, 0, len(extracted.Result))
+	for _, result := range extracted.Result {
+		if result.Identifier.Path != "" {
+			iDs = append(iDs, result.Identifier.Path)
+		}
+	}
+
+	return iDs, nil
+}
+
+// ORCIDSearchMaxRows is the max number of results that the ORCID search API returns per page.
+const ORCIDSearchMaxRows = 1000
+
+// ORCIDSearchResult defines a single ORCID expanded search result.
+type ORCIDSearchResult struct {
+	ORCID       string `json:"orcid"`
+	GivenNames  string `json:"given_names"`
+	FamilyNames string `json:"family_names"`
+	CreditName  string `json:"credit_name"`
+}
+
+// ORCIDSearchResults defines a single page of ORCID search results.
+type ORCIDSearchResults struct {
+	// Results is the current page of the search results.
+	Results []ORCIDSearchResult `json:"results"`
+
+	// Total is the total number of the matching records (across all pages).
+	Total int `json:"total"`
+}
+
+// SearchByName searches the ORCID registry for users with the specified
+// given and/or family names and returns a page of the matching candidates
+// (eg. for disambiguation UIs).
+//
+// offset and limit control the pagination (limit <= 0 fallbacks to 10
+// and it is capped to [ORCIDSearchMaxRows]).
+//
+// Similar to [ORCID.SearchByEmail] the search uses the app client credentials token.
+//
+// The expanded search is available only in the v3.0 API and
+// [ErrUnsupportedORCIDAPIVersion] is returned for older APIVersion.
+func (p *ORCID) SearchByName(ctx context.Context, given string, family string, offset int, limit int) (*ORCIDSearchResults, error) {
+	if err := p.requireAPIVersion30("/expanded-search"); err != nil {
+		return nil, err
+	}
+
+	query := orcidNameSearchQuery(given, family)
+	if query == "" {
+		return nil, errors.New("missing ORCID search given or family name")
+	}
+
+	token, err := p.ClientCredentialsToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.searchURL("expanded-search", orcidSearchParams(query, offset, limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Result []struct {
+			ORCID       string `json:"orcid-id"`
+			GivenNames  string `json:"given-names"`
+			FamilyNames string `json:"family-names"`
+			CreditName  string `json:"credit-name"`
+		} `json:"expanded-result"` // null if there are no matches
+		NumFound int `json:"num-found"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	results := &ORCIDSearchResults{
+		Results: make([]ORCIDSearchResult, 0, len(extracted.Result)),
+		Total:   extracted.NumFound,
+	}
+	for _, r := range extracted.Result {
+		results.Results = append(results.Results, ORCIDSearchResult(r))
+	}
+
+	return results, nil
+}
+
+// orcidNameSearchQuery builds the ORCID search query for the specified names
+// (returns empty string if both names are empty).
+func orcidNameSearchQuery(given string, family string) string {
+	var terms []string
+
+	if given = strings.TrimSpace(given); given != "" {
+		terms = append(terms, "given-names:"+orcidLuceneEscape(given))
+	}
+
+	if family = strings.TrimSpace(family); family != "" {
+		terms = append(terms, "family-name:"+orcidLuceneEscape(family))
+	}
+
+	return strings.Join(terms, " AND ")
+}
+
+// orcidSearchParams returns the ORCID search query params with normalized pagination.
+func orcidSearchParams(query string, offset int, limit int) url.Values {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if limit <= 0 {
+		limit = 10
+	} else if limit > ORCIDSearchMaxRows {
+		limit = ORCIDSearchMaxRows
+	}
+
+	return url.Values{
+		"q":     {query},
+		"start": {strconv.Itoa(offset)},
+		"rows":  {strconv.Itoa(limit)},
+	}
+}
+
+// searchURL returns the url of the specified ORCID search endpoint (eg. "search").
+func (p *ORCID) searchURL(endpoint string, params url.Values) string {
+	return p.apiURL(endpoint, "") + "?" + params.Encode()
+}
+
+// orcidLuceneEscape escapes the Lucene query syntax special characters
+// so that the value could be safely used as search term.
+func orcidLuceneEscape(value string) string {
+	var sb strings.Builder
+	sb.Grow(len(value))
+
+	for _, r := range value {
+		if strings.ContainsRune(`+-&|!(){}[]^"~*?:\/ `, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestOrcidLuceneEscape(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"josiah@example.com", "josiah@example.com"},
+		{"josiah+test@example.com", `josiah\+test@example.com`},
+		{`a-b&&c||d!(e){f}[g]^"h"~i*j?k:l\m/n o`, `a\-b\&\&c\|\|d\!\(e\)\{f\}\[g\]\^\"h\"\~i\*j\?k\:l\\m\/n\ o`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := orcidLuceneEscape(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+// testORCIDSearchServer starts a mock ORCID server that issues client
+// credentials tokens and serves the search responses for the specified queries.
+func testORCIDSearchServer(t *testing.T, endpoint string, responses map[string]string) *ORCID {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/oauth/token":
+			fmt.Fprint(w, `{"access_token":"public_token","token_type":"bearer","expires_in":631138518,"scope":"/read-public"}`)
+		case "/v3.0/" + endpoint:
+			if r.Header.Get("Authorization") != "Bearer public_token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			key := r.URL.Query().Get("q")
+			if r.URL.Query().Has("start") {
+				key += "|" + r.URL.Query().Get("start") + "|" + r.URL.Query().Get("rows")
+			}
+
+			response, ok := responses[key]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"response-code":400,"developer-message":"unexpected query %s"}`, key)
+				return
+			}
+
+			fmt.Fprint(w, response)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewORCIDProvider()
+	p.SetClientId("test_client_id")
+	p.SetClientSecret("test_client_secret")
+	p.SetTokenURL(srv.URL + "/oauth/token")
+	p.APIBaseURL = srv.URL
+
+	return p
+}
+
+func TestORCIDSearchByEmail(t *testing.T) {
+	p := testORCIDSearchServer(t, "search", map[string]string{
+		"email:josiah@example.com": `{
+			"result": [
+				{"orcid-identifier": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"}}
+			],
+			"num-found": 1
+		}`,
+		`email:shared\+list@example.com`: `{
+			"result": [
+				{"orcid-identifier": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"}},
+				{"orcid-identifier": {"uri": "https://orcid.org/0000-0002-1694-233X", "path": "0000-0002-1694-233X", "host": "orcid.org"}}
+			],
+			"num-found": 2
+		}`,
+		"email:missing@example.com": `{"result": null, "num-found": 0}`,
+	})
+
+	scenarios := []struct {
+		email       string
+		expectError bool
+		expected    []string
+	}{
+		{"", true, nil},
+		{"josiah@example.com", false, []string{"0000-0002-1825-0097"}},
+		{" shared+list@example.com ", false, []string{"0000-0002-1825-0097", "0000-0002-1694-233X"}},
+		{"missing@example.com", false, []string{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.email, func(t *testing.T) {
+			iDs, err := p.SearchByEmail(context.Background(), s.email)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && (iDs == nil || !slices.Equal(iDs, s.expected)) {
+				t.Fatalf("Expected iDs %v, got %v", s.expected, iDs)
+			}
+		})
+	}
+}
+
+func TestOrcidNameSearchQuery(t *testing.T) {
+	scenarios := []struct {
+		given    string
+		family   string
+		expected string
+	}{
+		{"", "", ""},
+		{" ", " ", ""},
+		{"Josiah", "", "given-names:Josiah"},
+		{"", "Carberry", "family-name:Carberry"},
+		{"Josiah Stinkney", "O'Carberry-Smith", `given-names:Josiah\ Stinkney AND family-name:O'Carberry\-Smith`},
+		{"J*", "(Carberry)", `given-names:J\* AND family-name:\(Carberry\)`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.given+"_"+s.family, func(t *testing.T) {
+			if v := orcidNameSearchQuery(s.given, s.family); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestOrcidSearchParams(t *testing.T) {
+	scenarios := []struct {
+		offset        int
+		limit         int
+		expectedStart string
+		expectedRows  string
+	}{
+		{0, 0, "0", "10"},
+		{-5, -1, "0", "10"},
+		{20, 5, "20", "5"},
+		{0, 5000, "0", "1000"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%d", s.offset, s.limit), func(t *testing.T) {
+			params := orcidSearchParams("q", s.offset, s.limit)
+
+			if v := params.Get("start"); v != s.expectedStart {
+				t.Fatalf("Expected start %q, got %q", s.expectedStart, v)
+			}
+
+			if v := params.Get("rows"); v != s.expectedRows {
+				t.Fatalf("Expected rows %q, got %q", s.expectedRows, v)
+			}
+		})
+	}
+}
+
+func TestORCIDSearchByName(t *testing.T) {
+	p := testORCIDSearchServer(t, "expanded-search", map[string]string{
+		"given-names:Josiah AND family-name:Carberry|0|2": `{
+			"expanded-result": [
+				{"orcid-id": "0000-0002-1825-0097", "given-names": "Josiah", "family-names": "Carberry", "credit-name": "Josiah S. Carberry", "other-name": [], "email": [], "institution-name": ["Brown University"]},
+				{"orcid-id": "0000-0002-1694-233X", "given-names": "Josiah", "family-names": "Carberry", "credit-name": null, "other-name": [], "email": [], "institution-name": []}
+			],
+			"num-found": 3
+		}`,
+		"given-names:Josiah AND family-name:Carberry|2|2": `{
+			"expanded-result": [
+				{"orcid-id": "0000-0001-5109-3700", "given-names": "Josiah", "family-names": "Carberry"}
+			],
+			"num-found": 3
+		}`,
+		"family-name:Unknown|0|10": `{"expanded-result": null, "num-found": 0}`,
+	})
+
+	t.Run("missing names", func(t *testing.T) {
+		if _, err := p.SearchByName(context.Background(), "", " ", 0, 10); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("paginated results", func(t *testing.T) {
+		var iDs []string
+
+		for offset := 0; ; offset += 2 {
+			results, err := p.SearchByName(context.Background(), "Josiah", "Carberry", offset, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if results.Total != 3 {
+				t.Fatalf("Expected 3 total results, got %d", results.Total)
+			}
+
+			for _, r := range results.Results {
+				iDs = append(iDs, r.ORCID)
+			}
+
+			if offset+len(results.Results) >= results.Total {
+				break
+			}
+		}
+
+		expected := []string{"0000-0002-1825-0097", "0000-0002-1694-233X", "0000-0001-5109-3700"}
+		if !slices.Equal(iDs, expected) {
+			t.Fatalf("Expected iDs %v, got %v", expected, iDs)
+		}
+	})
+
+	t.Run("result names", func(t *testing.T) {
+		results, err := p.SearchByName(context.Background(), "Josiah", "Carberry", 0, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := ORCIDSearchResult{
+			ORCID:       "0000-0002-1825-0097",
+			GivenNames:  "Josiah",
+			FamilyNames: "Carberry",
+			CreditName:  "Josiah S. Carberry",
+		}
+		if results.Results[0] != expected {
+			t.Fatalf("Expected %#v, got %#v", expected, results.Results[0])
+		}
+	})
+
+	t.Run("api version 2.1", func(t *testing.T) {
+		p := testORCIDSearchServer(t, "expanded-search", nil)
+		p.APIVersion = ORCIDAPIVersion21
+
+		_, err := p.SearchByName(context.Background(), "Josiah", "Carberry", 0, 2)
+		if !errors.Is(err, ErrUnsupportedORCIDAPIVersion) {
+			t.Fatalf("Expected ErrUnsupportedORCIDAPIVersion, got %v", err)
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		results, err := p.SearchByName(context.Background(), "", "Unknown", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if results.Total != 0 || results.Results == nil || len(results.Results) != 0 {
+			t.Fatalf("Expected empty non-nil results, got %#v", results)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// testORCIDServeriD is the iD of the user served by newTestORCIDServer.
+const testORCIDServeriD = "0000-0002-1825-0097"
+
+const testORCIDEmailJSON = `{
+	"last-modified-date": {"value": 1460757617080},
+	"email": [
+		{
+			"email": "josiah.limited@example.com",
+			"path": null,
+			"visibility": "limited",
+			"verified": true,
+			"primary": true,
+			"put-code": null
+		}
+	],
+	"path": "/0000-0002-1825-0097/email"
+}`
+
+const testORCIDTokenJSON = `{
+	"access_token": "test_access_token",
+	"token_type": "bearer",
+	"refresh_token": "test_refresh_token",
+	"expires_in": 631138518,
+	"scope": "/authenticate",
+	"name": "Josiah Carberry",
+	"orcid": "0000-0002-1825-0097"
+}`
+
+// testORCIDServer is a mock ORCID server that serves canned token and API responses.
+type testORCIDServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []string
+	responses map[string]testORCIDResponse
+}
+
+type testORCIDResponse struct {
+	status int
+	body   string
+}
+
+// newTestORCIDServer starts a new mock ORCID server with the default
+// fixtures for the token, person, record, email, works, employments,
+// educations and fundings endpoints.
+//
+// The server is closed automatically at the end of the test.
+func newTestORCIDServer(t *testing.T) *testORCIDServer {
+	t.Helper()
+
+	srv := &testORCIDServer{
+		responses: map[string]testORCIDResponse{
+			"/oauth/token":                                {http.StatusOK, testORCIDTokenJSON},
+			"/v3.0/" + testORCIDServeriD + "/person":      {http.StatusOK, testORCIDPersonJSON},
+			"/v3.0/" + testORCIDServeriD + "/record":      {http.StatusOK, testORCIDRecordJSON},
+			"/v3.0/" + testORCIDServeriD + "/email":       {http.StatusOK, testORCIDEmailJSON},
+			"/v3.0/" + testORCIDServeriD + "/works":       {http.StatusOK, testORCIDWorksJSON},
+			"/v3.0/" + testORCIDServeriD + "/employments": {http.StatusOK, testORCIDEmploymentsJSON},
+			"/v3.0/" + testORCIDServeriD + "/educations":  {http.StatusOK, testORCIDEducationsJSON},
+			"/v3.0/" + testORCIDServeriD + "/fundings":    {http.StatusOK, testORCIDFundingsJSON},
+		},
+	}
+
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// setResponse replaces the response of the specified path
+// (eg. "/v3.0/0000-0002-1825-0097/works").
+func (srv *testORCIDServer) setResponse(path string, status int, body string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.responses[path] = testORCIDResponse{status, body}
+}
+
+// requestedPaths returns the paths of all received requests in the order they were received.
+func (srv *testORCIDServer) requestedPaths() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return append([]string(nil), srv.requests...)
+}
+
+// provider returns a new ORCID provider configured to use the mock server.
+func (srv *testORCIDServer) provider(opts ...ORCIDOption) *ORCID {
+	p := NewORCIDProvider(opts...)
+	p.SetClientId("test_client_id")
+	p.SetClientSecret("test_client_secret")
+	p.SetTokenURL(srv.URL + "/oauth/token")
+	p.APIBaseURL = srv.URL
+	p.MemberAPIBaseURL = srv.URL
+	p.SiteURL = srv.URL
+	p.RetryBaseDelay = 0
+	p.SetClient(srv.Client())
+
+	return p
+}
+
+// token returns a new OAuth2 token for the mock server user.
+func (srv *testORCIDServer) token() *oauth2.Token {
+	return (&oauth2.Token{AccessToken: "test_access_token"}).WithExtra(map[string]any{"orcid": testORCIDServeriD})
+}
+
+func (srv *testORCIDServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.mu.Lock()
+	srv.requests = append(srv.requests, r.URL.Path)
+	response, ok := srv.responses[r.URL.Path]
+	srv.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"response-code":404,"developer-message":"404 Not Found: %s","user-message":"The resource was not found.","error-code":9016}`, r.URL.Path)
+		return
+	}
+
+	if r.URL.Path != "/oauth/token" && r.Header.Get("Authorization") != "Bearer test_access_token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_token","error_description":"Invalid access token"}`)
+		return
+	}
+
+	w.WriteHeader(response.status)
+	fmt.Fprint(w, response.body)
+}
+
+func TestTestORCIDServer(t *testing.T) {
+	srv := newTestORCIDServer(t)
+
+	p := srv.provider()
+
+	token, err := p.FetchToken("test_code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Id != testORCIDServeriD || user.Email != "josiah@example.com" {
+		t.Fatalf("Expected user %s with josiah@example.com email, got %s with %s", testORCIDServeriD, user.Id, user.Email)
+	}
+
+	record, err := p.FetchRecord(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Name != "Josiah S. Carberry" {
+		t.Fatalf("Expected record name %q, got %q", "Josiah S. Carberry", record.Name)
+	}
+
+	works, err := p.FetchWorks(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(works) != 2 {
+		t.Fatalf("Expected 2 works, got %v", works)
+	}
+
+	employments, err := p.FetchEmployments(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employments) != 2 {
+		t.Fatalf("Expected 2 employments, got %v", employments)
+	}
+
+	emails := p.fetchMemberEmails(p.ctx, token, testORCIDServeriD)
+	if len(emails) != 1 || emails[0].Email != "josiah.limited@example.com" {
+		t.Fatalf("Expected the limited email, got %v", emails)
+	}
+
+	srv.setResponse("/v3.0/"+testORCIDServeriD+"/works", http.StatusInternalServerError, `{"response-code":500}`)
+	if _, err := p.FetchWorks(token); err == nil {
+		t.Fatal("Expected the overridden works response to fail")
+	}
+
+	paths := strings.Join(srv.requestedPaths(), ",")
+	if !strings.HasPrefix(paths, "/oauth/token,/v3.0/"+testORCIDServeriD+"/person,") {
+		t.Fatalf("Expected the token and person requests to be recorded first, got %s", paths)
+	}
+}
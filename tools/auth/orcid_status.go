@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Ping checks whether the configured ORCID environment is reachable
+// and the provider client credentials are valid (eg. for readiness probes).
+//
+// It requests a new "/read-public" client credentials token
+// (the cached token is intentionally not used) and then the
+// lightweight API /status endpoint.
+//
+// It returns nil on success or an error describing the failed check.
+func (p *ORCID) Ping(ctx context.Context) error {
+	if p.clientId == "" || p.clientSecret == "" {
+		return errors.New("ORCID ping failed: missing client id or secret")
+	}
+
+	token, err := p.requestClientCredentialsToken(ctx)
+	if err != nil {
+		return fmt.Errorf("ORCID ping failed: %w", err)
+	}
+
+	data, err := p.fetchJSON(ctx, token, p.apiURL("status", ""))
+	if err != nil {
+		return fmt.Errorf("ORCID ping failed: %w", err)
+	}
+
+	// eg. {"tomcatUp":true,"dbConnectionOk":true,"readOnlyDbConnectionOk":true,"overallOk":true}
+	status := map[string]any{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("ORCID ping failed: invalid status response: %w", err)
+	}
+
+	if ok, exists := status["overallOk"].(bool); exists && !ok {
+		return fmt.Errorf("ORCID ping failed: the API status is not ok: %s", data)
+	}
+
+	return nil
+}
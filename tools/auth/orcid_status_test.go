@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestORCIDPing(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		tokenStatus   int
+		status        int
+		statusBody    string
+		unreachable   bool
+		expectedError string
+	}{
+		{"success", http.StatusOK, http.StatusOK, `{"tomcatUp":true,"dbConnectionOk":true,"overallOk":true}`, false, ""},
+		{"invalid credentials", http.StatusUnauthorized, http.StatusOK, `{"overallOk":true}`, false, "invalid_client"},
+		{"not ok status", http.StatusOK, http.StatusOK, `{"tomcatUp":true,"dbConnectionOk":false,"overallOk":false}`, false, "status is not ok"},
+		{"status error", http.StatusOK, http.StatusServiceUnavailable, `{}`, false, "(503)"},
+		{"unreachable", http.StatusOK, http.StatusOK, `{}`, true, "ORCID ping failed"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			if s.tokenStatus != http.StatusOK {
+				srv.setResponse("/oauth/token", s.tokenStatus, `{"error":"invalid_client","error_description":"Client not found"}`)
+			}
+			srv.setResponse("/v3.0/status", s.status, s.statusBody)
+
+			p := srv.provider()
+			p.MaxRetries = 0
+
+			if s.unreachable {
+				srv.Close()
+			}
+
+			err := p.Ping(context.Background())
+
+			if s.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Expected nil error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), s.expectedError) {
+				t.Fatalf("Expected error containing %q, got %v", s.expectedError, err)
+			}
+		})
+	}
+
+	t.Run("missing client credentials", func(t *testing.T) {
+		if err := NewORCIDProvider().Ping(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrORCIDSchemaMismatch is returned when [ORCID.StrictDecoding] is enabled
+// and the ORCID API response has fields that are not part of the known schema.
+var ErrORCIDSchemaMismatch = errors.New("the ORCID API response doesn't match the known schema")
+
+// decodeORCIDStrict decodes data into v rejecting the unknown fields.
+func decodeORCIDStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrORCIDSchemaMismatch, err)
+	}
+
+	return nil
+}
+
+// orcidStrictPerson defines the known v3.0 /person response schema
+// used by the [ORCID.StrictDecoding] check.
+//
+// The leaf value objects (eg. {"value":"..."}) and the sources are not
+// checked because they are not used by the person parser.
+type orcidStrictPerson struct {
+	LastModifiedDate json.RawMessage `json:"last-modified-date"`
+	Path             json.RawMessage `json:"path"`
+	Name             *struct {
+		CreatedDate      json.RawMessage `json:"created-date"`
+		LastModifiedDate json.RawMessage `json:"last-modified-date"`
+		GivenNames       json.RawMessage `json:"given-names"`
+		FamilyName       json.RawMessage `json:"family-name"`
+		CreditName       json.RawMessage `json:"credit-name"`
+		Source           json.RawMessage `json:"source"`
+		Visibility       json.RawMessage `json:"visibility"`
+		Path             json.RawMessage `json:"path"`
+	} `json:"name"`
+	OtherNames *struct {
+		orcidStrictSection
+		OtherName []struct {
+			orcidStrictElement
+			Content json.RawMessage `json:"content"`
+		} `json:"other-name"`
+	} `json:"other-names"`
+	Biography *struct {
+		CreatedDate      json.RawMessage `json:"created-date"`
+		LastModifiedDate json.RawMessage `json:"last-modified-date"`
+		Content          json.RawMessage `json:"content"`
+		Visibility       json.RawMessage `json:"visibility"`
+		Path             json.RawMessage `json:"path"`
+	} `json:"biography"`
+	ResearcherURLs *struct {
+		orcidStrictSection
+		ResearcherURL []struct {
+			orcidStrictElement
+			URLName json.RawMessage `json:"url-name"`
+			URL     json.RawMessage `json:"url"`
+		} `json:"researcher-url"`
+	} `json:"researcher-urls"`
+	Emails *struct {
+		orcidStrictSection
+		Email []struct {
+			orcidStrictElement
+			Email    json.RawMessage `json:"email"`
+			Verified json.RawMessage `json:"verified"`
+			Primary  json.RawMessage `json:"primary"`
+		} `json:"email"`
+	} `json:"emails"`
+	Addresses *struct {
+		orcidStrictSection
+		Address []struct {
+			orcidStrictElement
+			Country json.RawMessage `json:"country"`
+			Primary json.RawMessage `json:"primary"`
+		} `json:"address"`
+	} `json:"addresses"`
+	Keywords *struct {
+		orcidStrictSection
+		Keyword []struct {
+			orcidStrictElement
+			Content json.RawMessage `json:"content"`
+		} `json:"keyword"`
+	} `json:"keywords"`
+	ExternalIdentifiers *struct {
+		orcidStrictSection
+		ExternalIdentifier []struct {
+			orcidStrictElement
+			Type         json.RawMessage `json:"external-id-type"`
+			Value        json.RawMessage `json:"external-id-value"`
+			URL          json.RawMessage `json:"external-id-url"`
+			Relationship json.RawMessage `json:"external-id-relationship"`
+		} `json:"external-identifier"`
+	} `json:"external-identifiers"`
+}
+
+// orcidStrictSection defines the common fields of a person section (eg. "emails").
+type orcidStrictSection struct {
+	LastModifiedDate json.RawMessage `json:"last-modified-date"`
+	Path             json.RawMessage `json:"path"`
+}
+
+// orcidStrictElement defines the common fields of a person section element (eg. a single email).
+type orcidStrictElement struct {
+	CreatedDate      json.RawMessage `json:"created-date"`
+	LastModifiedDate json.RawMessage `json:"last-modified-date"`
+	Source           json.RawMessage `json:"source"`
+	Visibility       json.RawMessage `json:"visibility"`
+	Path             json.RawMessage `json:"path"`
+	PutCode          json.RawMessage `json:"put-code"`
+	DisplayIndex     json.RawMessage `json:"display-index"`
+}
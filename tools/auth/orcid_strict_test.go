@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestORCIDStrictDecoding(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		person      string
+		strict      bool
+		expectError bool
+	}{
+		{"known schema (lenient)", testORCIDPersonJSON, false, false},
+		{"known schema (strict)", testORCIDPersonJSON, true, false},
+		{"null person (strict)", `null`, true, false},
+		{
+			"unknown top level field (lenient)",
+			`{"name":{"given-names":{"value":"Josiah"}},"injected":true}`,
+			false,
+			false,
+		},
+		{
+			"unknown top level field (strict)",
+			`{"name":{"given-names":{"value":"Josiah"}},"injected":true}`,
+			true,
+			true,
+		},
+		{
+			"primary address (strict)",
+			`{"name":{"given-names":{"value":"Josiah"}},"addresses":{"address":[{"country":{"value":"GB"},"primary":true}]}}`,
+			true,
+			false,
+		},
+		{
+			"unknown nested field (strict)",
+			strings.Replace(testORCIDPersonJSON, `"verified": true,`, `"verified": true, "verified-date": null,`, 1),
+			true,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewORCIDProvider()
+			p.StrictDecoding = s.strict
+
+			_, name, _, err := p.parsePerson([]byte(s.person), "0000-0002-1825-0097")
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if !errors.Is(err, ErrORCIDSchemaMismatch) {
+					t.Fatalf("Expected ErrORCIDSchemaMismatch, got %v", err)
+				}
+				return
+			}
+
+			if s.person != "null" && name == "" {
+				t.Fatal("Expected non-empty name")
+			}
+		})
+	}
+}
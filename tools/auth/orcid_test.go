@@ -0,0 +1,1832 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+func TestValidateORCIDiD(t *testing.T) {
+	scenarios := []struct {
+		id          string
+		expectError bool
+	}{
+		{"", true},
+		{"0000-0002-1825-009", true},
+		{"0000-0002-1825-00977", true},
+		{"0000000218250097", true},
+		{"0000-0002-1825-0098", true},
+		{"0000-0002-1825-009X", true},
+		{"0000-0002-18a5-0097", true},
+		{"0000-0002-1825/0097", true},
+		{"../../0002-1825-0097", true},
+		{"0000-0002-1825-0097", false},
+		{"0000-0001-5109-3700", false},
+		{"0000-0002-1694-233X", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.id, func(t *testing.T) {
+			err := validateORCIDiD(s.id)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr && !errors.Is(err, ErrInvalidORCIDiD) {
+				t.Fatalf("Expected ErrInvalidORCIDiD, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeORCIDiD(t *testing.T) {
+	scenarios := []struct {
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{"0000-0002-1825-0097", "0000-0002-1825-0097", false},
+		{"  0000-0002-1825-0097\n", "0000-0002-1825-0097", false},
+		{"orcid.org/0000-0002-1825-0097", "0000-0002-1825-0097", false},
+		{"https://orcid.org/0000-0002-1825-0097", "0000-0002-1825-0097", false},
+		{"http://www.orcid.org/0000-0002-1825-0097/", "0000-0002-1825-0097", false},
+		{"https://sandbox.orcid.org/0000-0002-1694-233x//", "0000-0002-1694-233X", false},
+		{"000000021825O097", "", true},
+		{"0000000218250097", "0000-0002-1825-0097", false},
+		{"https://orcid.org/0000-0002-1825-0098", "", true},
+		{"https://orcid.org/", "", true},
+		{"", "", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.input, func(t *testing.T) {
+			iD, err := NormalizeORCIDiD(s.input)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr && !errors.Is(err, ErrInvalidORCIDiD) {
+				t.Fatalf("Expected ErrInvalidORCIDiD, got %v", err)
+			}
+
+			if iD != s.expected {
+				t.Fatalf("Expected iD %q, got %q", s.expected, iD)
+			}
+		})
+	}
+}
+
+func TestORCIDProfileURL(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		provider    *ORCID
+		iD          string
+		expectError bool
+		expectedURL string
+	}{
+		{"production", NewORCIDProvider(), "0000-0002-1825-0097", false, "https://orcid.org/0000-0002-1825-0097"},
+		{"sandbox", NewORCIDSandboxProvider(), "0000-0002-1825-0097", false, "https://sandbox.orcid.org/0000-0002-1825-0097"},
+		{"member", NewORCIDMemberProvider(), "0000-0002-1694-233X", false, "https://orcid.org/0000-0002-1694-233X"},
+		{"iD URI", NewORCIDSandboxProvider(), "https://orcid.org/0000-0002-1825-0097/", false, "https://sandbox.orcid.org/0000-0002-1825-0097"},
+		{"invalid iD", NewORCIDProvider(), "0000-0002-1825-0098", true, ""},
+		{"empty iD", NewORCIDSandboxProvider(), "", true, ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			url, err := s.provider.ProfileURL(s.iD)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr && !errors.Is(err, ErrInvalidORCIDiD) {
+				t.Fatalf("Expected ErrInvalidORCIDiD, got %v", err)
+			}
+
+			if url != s.expectedURL {
+				t.Fatalf("Expected url %q, got %q", s.expectedURL, url)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserMissingiD(t *testing.T) {
+	scenarios := []struct {
+		name  string
+		token *oauth2.Token
+	}{
+		{"no extra fields", &oauth2.Token{AccessToken: "test"}},
+		{"empty orcid field", (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": ""})},
+		{"non string orcid field", (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": 123})},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := NewORCIDProvider().FetchAuthUser(s.token)
+
+			if !errors.Is(err, ErrMissingORCIDiD) {
+				t.Fatalf("Expected ErrMissingORCIDiD, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), "Failed to get ORCID iD from OAuth2 token") {
+				t.Fatalf("Expected the original error message to be preserved, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestNewORCIDProviderWithScopes(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		scopes         []string
+		expectError    bool
+		expectedScopes []string
+	}{
+		{"no scopes", nil, false, []string{"/authenticate", "openid"}},
+		{"empty scope", []string{"/read-limited", ""}, true, nil},
+		{"unknown scope", []string{"/read-limited", "/unknown"}, true, nil},
+		{"valid scopes", []string{"/read-limited", "/activities/update"}, false, []string{"/authenticate", "/read-limited", "/activities/update"}},
+		{"duplicated scopes", []string{"/authenticate", "openid", "openid"}, false, []string{"/authenticate", "openid"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p, err := NewORCIDProviderWithScopes(s.scopes...)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if !slices.Equal(p.Scopes(), s.expectedScopes) {
+				t.Fatalf("Expected scopes %v, got %v", s.expectedScopes, p.Scopes())
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /v3.0/{id}/person
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) != 4 || parts[3] != "person" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name":{"credit-name":{"value":%q}}}`, parts[2])
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(iD string) {
+			defer wg.Done()
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": iD})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Errorf("[%s] Expected nil error, got %v", iD, err)
+				return
+			}
+
+			if user.Id != iD || user.Name != iD {
+				t.Errorf("[%s] Expected user with the same iD and name, got %q and %q", iD, user.Id, user.Name)
+			}
+		}(testORCIDiD(i))
+	}
+	wg.Wait()
+}
+
+// testORCIDiD generates a valid ORCID iD from the provided number.
+func testORCIDiD(n int) string {
+	base := fmt.Sprintf("%015d", n)
+
+	var total int
+	for _, c := range base {
+		total = (total + int(c-'0')) * 2
+	}
+
+	check := (12 - total%11) % 11
+
+	checkStr := "X"
+	if check < 10 {
+		checkStr = fmt.Sprint(check)
+	}
+
+	id := base + checkStr
+
+	return id[0:4] + "-" + id[4:8] + "-" + id[8:12] + "-" + id[12:16]
+}
+
+func TestORCIDFetchAuthUserIdToken(t *testing.T) {
+	iD := "0000-0002-1825-0097"
+
+	var totalRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":{"given-names":{"value":"Josiah"},"family-name":{"value":"Carberry"}},"emails":{"email":[{"email":"test@example.com"}]}}`)
+	}))
+	defer srv.Close()
+
+	scenarios := []struct {
+		name             string
+		skipEmail        bool
+		claims           map[string]any
+		expectError      bool
+		expectName       string
+		expectEmail      string
+		expectedRequests int32
+	}{
+		{
+			"no id_token",
+			true,
+			nil,
+			false,
+			"Josiah Carberry",
+			"test@example.com",
+			1,
+		},
+		{
+			"id_token with name and SkipEmail",
+			true,
+			map[string]any{"sub": iD, "name": "J. Carberry"},
+			false,
+			"J. Carberry",
+			"",
+			0,
+		},
+		{
+			"id_token with given_name and family_name and SkipEmail",
+			true,
+			map[string]any{"sub": iD, "given_name": "Josiah", "family_name": "S. Carberry"},
+			false,
+			"Josiah S. Carberry",
+			"",
+			0,
+		},
+		{
+			"id_token without names and SkipEmail",
+			true,
+			map[string]any{"sub": iD},
+			false,
+			"Josiah Carberry",
+			"test@example.com",
+			1,
+		},
+		{
+			"id_token without SkipEmail",
+			false,
+			map[string]any{"sub": iD, "name": "J. Carberry"},
+			false,
+			"Josiah Carberry",
+			"test@example.com",
+			1,
+		},
+		{
+			"id_token with different sub",
+			true,
+			map[string]any{"sub": "0000-0001-5109-3700", "name": "J. Carberry"},
+			true,
+			"",
+			"",
+			0,
+		},
+		{
+			"expired id_token",
+			true,
+			map[string]any{"sub": iD, "name": "J. Carberry", "exp": 1},
+			true,
+			"",
+			"",
+			0,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			totalRequests.Store(0)
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+			p.SkipEmail = s.skipEmail
+			p.SkipIdTokenVerification = true
+
+			extra := map[string]any{"orcid": iD}
+			if s.claims != nil {
+				extra["id_token"] = testUnsignedJWT(t, s.claims)
+			}
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(extra)
+
+			user, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if total := totalRequests.Load(); total != s.expectedRequests {
+				t.Fatalf("Expected %d requests, got %d", s.expectedRequests, total)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if user.Id != iD {
+				t.Fatalf("Expected id %q, got %q", iD, user.Id)
+			}
+
+			if user.Name != s.expectName {
+				t.Fatalf("Expected name %q, got %q", s.expectName, user.Name)
+			}
+
+			if user.Email != s.expectEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectEmail, user.Email)
+			}
+
+			// both the id_token claims and the person RawUser are versioned
+			if v := user.RawUser["raw_schema_version"]; v != ORCIDRawUserSchemaVersion {
+				t.Fatalf("Expected raw_schema_version %d, got %#v", ORCIDRawUserSchemaVersion, v)
+			}
+		})
+	}
+}
+
+// testUnsignedJWT returns an unsigned ("alg":"none") JWT with the provided claims.
+func testUnsignedJWT(t *testing.T, claims map[string]any) string {
+	header, err := json.Marshal(map[string]any{"alg": "none", "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+func TestORCIDFetchAuthUserIdTokenVerification(t *testing.T) {
+	iD := "0000-0002-1825-0097"
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var totalJWKsRequests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/jwks" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		totalJWKsRequests.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"kid": "test_kid",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	sign := func(signKey *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(signKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":  iD,
+			"iss":  srv.URL,
+			"aud":  "test_client_id",
+			"name": "Josiah Carberry",
+			"exp":  time.Now().Add(1 * time.Hour).Unix(),
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		idToken     func() string
+		expectError bool
+	}{
+		{
+			"valid signature",
+			func() string { return sign(key, "test_kid", validClaims()) },
+			false,
+		},
+		{
+			"unsigned token",
+			func() string { return testUnsignedJWT(t, validClaims()) },
+			true,
+		},
+		{
+			"invalid signature",
+			func() string { return sign(otherKey, "test_kid", validClaims()) },
+			true,
+		},
+		{
+			"missing kid",
+			func() string { return sign(key, "", validClaims()) },
+			true,
+		},
+		{
+			"unknown kid",
+			func() string { return sign(key, "missing_kid", validClaims()) },
+			true,
+		},
+		{
+			"invalid iss",
+			func() string {
+				claims := validClaims()
+				claims["iss"] = "https://example.com"
+				return sign(key, "test_kid", claims)
+			},
+			true,
+		},
+		{
+			"invalid aud",
+			func() string {
+				claims := validClaims()
+				claims["aud"] = "other_client_id"
+				return sign(key, "test_kid", claims)
+			},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewORCIDProvider()
+			p.SetClientId("test_client_id")
+			p.SiteURL = srv.URL
+			p.APIBaseURL = srv.URL // the person endpoint is intentionally missing
+			p.SkipEmail = true
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{
+				"orcid":    iD,
+				"id_token": s.idToken(),
+			})
+
+			user, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && user.Name != "Josiah Carberry" {
+				t.Fatalf("Expected the id_token name, got %q", user.Name)
+			}
+		})
+	}
+
+	// 1 for the valid (and then cached) key + 1 for the unknown kid
+	if total := totalJWKsRequests.Load(); total != 2 {
+		t.Fatalf("Expected 2 jwks requests, got %d", total)
+	}
+}
+
+func TestORCIDRefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.PostForm.Get("grant_type") != "refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"unsupported_grant_type","error_description":"Unsupported grant type"}`)
+			return
+		}
+
+		if r.PostForm.Get("refresh_token") != "valid_refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Invalid refresh token"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"access_token":"new_access_token","token_type":"bearer","refresh_token":"new_refresh_token","expires_in":631138518,"scope":"/authenticate","name":"Josiah Carberry","orcid":"0000-0002-1825-0097"}`)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.SetClientId("test_client_id")
+	p.SetClientSecret("test_client_secret")
+	p.SetTokenURL(srv.URL)
+
+	t.Run("empty refresh token", func(t *testing.T) {
+		_, err := p.RefreshToken(context.Background(), "")
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("invalid refresh token", func(t *testing.T) {
+		_, err := p.RefreshToken(context.Background(), "invalid_refresh_token")
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "invalid_grant") || !strings.Contains(err.Error(), "Invalid refresh token") {
+			t.Fatalf("Expected the ORCID error code and description, got %v", err)
+		}
+	})
+
+	t.Run("valid refresh token", func(t *testing.T) {
+		token, err := p.RefreshToken(context.Background(), "valid_refresh_token")
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+
+		if token.AccessToken != "new_access_token" {
+			t.Fatalf("Expected access token %q, got %q", "new_access_token", token.AccessToken)
+		}
+
+		if token.RefreshToken != "new_refresh_token" {
+			t.Fatalf("Expected refresh token %q, got %q", "new_refresh_token", token.RefreshToken)
+		}
+
+		if iD, _ := token.Extra("orcid").(string); iD != "0000-0002-1825-0097" {
+			t.Fatalf("Expected orcid extra %q, got %q", "0000-0002-1825-0097", iD)
+		}
+	})
+}
+
+// testORCIDPersonJSON is a trimmed down /person response of the
+// ORCID test record https://orcid.org/0000-0002-1825-0097.
+const testORCIDPersonJSON = `{
+	"last-modified-date": {"value": 1704067200000},
+	"name": {
+		"created-date": {"value": 1460757617078},
+		"last-modified-date": {"value": 1460757617078},
+		"given-names": {"value": "Josiah"},
+		"family-name": {"value": "Carberry"},
+		"credit-name": {"value": "Josiah S. Carberry"},
+		"source": null,
+		"visibility": "public",
+		"path": "0000-0002-1825-0097"
+	},
+	"other-names": {
+		"last-modified-date": {"value": 1460757617079},
+		"other-name": [
+			{"content": "Josiah Stinkney Carberry", "visibility": "public", "put-code": 1, "display-index": 2},
+			{"content": "J. S. Carberry", "visibility": "public", "put-code": 2, "display-index": 1}
+		],
+		"path": "/0000-0002-1825-0097/other-names"
+	},
+	"biography": {
+		"created-date": {"value": 1460757617079},
+		"last-modified-date": {"value": 1460757617079},
+		"content": "Josiah Carberry is a fictitious person. This account is used as a demonstration account by ORCID, CrossRef and others.",
+		"visibility": "public",
+		"path": "/0000-0002-1825-0097/biography"
+	},
+	"researcher-urls": {
+		"last-modified-date": {"value": 1460757617080},
+		"researcher-url": [
+			{
+				"url-name": "Brown University Page",
+				"url": {"value": "http://library.brown.edu/about/hay/carberry.php"},
+				"visibility": "public",
+				"put-code": 1,
+				"display-index": 2
+			},
+			{
+				"url-name": null,
+				"url": {"value": "https://en.wikipedia.org/wiki/Josiah_S._Carberry"},
+				"visibility": "public",
+				"put-code": 2,
+				"display-index": 1
+			}
+		],
+		"path": "/0000-0002-1825-0097/researcher-urls"
+	},
+	"emails": {
+		"last-modified-date": {"value": 1460757617080},
+		"email": [
+			{
+				"email": "josiah.old@example.com",
+				"path": null,
+				"visibility": "public",
+				"verified": true,
+				"primary": false,
+				"put-code": null
+			},
+			{
+				"email": "josiah@example.com",
+				"path": null,
+				"visibility": "public",
+				"verified": true,
+				"primary": true,
+				"put-code": null
+			}
+		],
+		"path": "/0000-0002-1825-0097/email"
+	},
+	"addresses": {
+		"last-modified-date": {"value": 1460757617081},
+		"address": [
+			{
+				"created-date": {"value": 1460757617081},
+				"last-modified-date": {"value": 1460757617081},
+				"source": null,
+				"country": {"value": "US"},
+				"visibility": "public",
+				"path": "/0000-0002-1825-0097/address/1",
+				"put-code": 1,
+				"display-index": 1
+			}
+		],
+		"path": "/0000-0002-1825-0097/address"
+	},
+	"keywords": {
+		"last-modified-date": {"value": 1460757617082},
+		"keyword": [
+			{"content": "psychoceramics", "visibility": "public", "put-code": 1, "display-index": 3},
+			{"content": "ionian philology", "visibility": "public", "put-code": 2, "display-index": 2},
+			{"content": "Psychoceramics", "visibility": "public", "put-code": 3, "display-index": 1}
+		],
+		"path": "/0000-0002-1825-0097/keywords"
+	},
+	"external-identifiers": {
+		"last-modified-date": {"value": 1460757617083},
+		"external-identifier": [
+			{
+				"external-id-type": "Scopus Author ID",
+				"external-id-value": "7007156898",
+				"external-id-url": {"value": "http://www.scopus.com/inward/authorDetails.url?authorID=7007156898&partnerID=MN8TOARS"},
+				"external-id-relationship": "self",
+				"visibility": "public",
+				"put-code": 1,
+				"display-index": 1
+			},
+			{
+				"external-id-type": "ResearcherID",
+				"external-id-value": "A-1234-2011",
+				"external-id-url": null,
+				"external-id-relationship": "self",
+				"visibility": "public",
+				"put-code": 2,
+				"display-index": 2
+			},
+			{
+				"external-id-type": "ISNI",
+				"external-id-value": "0000 0001 2146 438X",
+				"external-id-url": {"value": "http://isni.org/isni/000000012146438X"},
+				"external-id-relationship": "self",
+				"visibility": "public",
+				"put-code": 3,
+				"display-index": 3
+			}
+		],
+		"path": "/0000-0002-1825-0097/external-identifiers"
+	},
+	"path": "/0000-0002-1825-0097/person"
+}`
+
+func TestORCIDFetchAuthUserLastModified(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected string
+	}{
+		{
+			"epoch millis",
+			testORCIDPersonJSON,
+			"2024-01-01 00:00:00.000Z",
+		},
+		{
+			"epoch millis with fraction",
+			`{"last-modified-date":{"value":1460757617078}}`,
+			"2016-04-15 22:00:17.078Z",
+		},
+		{
+			"missing last-modified-date",
+			`{"last-modified-date":null}`,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			lastModified, ok := user.RawUser["last_modified"].(types.DateTime)
+
+			if s.expected == "" {
+				if _, exists := user.RawUser["last_modified"]; exists {
+					t.Fatalf("Expected no last_modified key, got %v", user.RawUser["last_modified"])
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("Expected types.DateTime last_modified, got %T", user.RawUser["last_modified"])
+			}
+
+			if lastModified.String() != s.expected {
+				t.Fatalf("Expected last_modified %q, got %q", s.expected, lastModified.String())
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserNameStrategy(t *testing.T) {
+	const fullName = `{"name":{"given-names":{"value":"Josiah"},"family-name":{"value":"Carberry"},"credit-name":{"value":"Josiah S. Carberry"}}}`
+	const withoutCredit = `{"name":{"given-names":{"value":"Josiah"},"family-name":{"value":"Carberry"},"credit-name":null}}`
+	const onlyGiven = `{"name":{"given-names":{"value":"Josiah"},"family-name":null,"credit-name":null}}`
+	const onlyCredit = `{"name":{"given-names":null,"family-name":null,"credit-name":{"value":"J. S. Carberry"}}}`
+	const privateName = `{"name":null}`
+
+	scenarios := []struct {
+		name     string
+		strategy ORCIDNameStrategy
+		person   string
+		expected string
+	}{
+		{"CreditThenGiven with credit name", ORCIDNameCreditThenGiven, fullName, "Josiah S. Carberry"},
+		{"CreditThenGiven without credit name", ORCIDNameCreditThenGiven, withoutCredit, "Josiah Carberry"},
+		{"CreditThenGiven with only given names", ORCIDNameCreditThenGiven, onlyGiven, "Josiah"},
+		{"CreditThenGiven with private name", ORCIDNameCreditThenGiven, privateName, ""},
+		{"GivenFamily with credit name", ORCIDNameGivenFamily, fullName, "Josiah Carberry"},
+		{"GivenFamily with only given names", ORCIDNameGivenFamily, onlyGiven, "Josiah"},
+		{"GivenFamily with only credit name", ORCIDNameGivenFamily, onlyCredit, "J. S. Carberry"},
+		{"GivenFamily with private name", ORCIDNameGivenFamily, privateName, ""},
+		{"FamilyGiven with credit name", ORCIDNameFamilyGiven, fullName, "Carberry, Josiah"},
+		{"FamilyGiven without credit name", ORCIDNameFamilyGiven, withoutCredit, "Carberry, Josiah"},
+		{"FamilyGiven with only given names", ORCIDNameFamilyGiven, onlyGiven, "Josiah"},
+		{"FamilyGiven with only credit name", ORCIDNameFamilyGiven, onlyCredit, "J. S. Carberry"},
+		{"FamilyGiven with private name", ORCIDNameFamilyGiven, privateName, ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, s.person)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider(WithORCIDNameStrategy(s.strategy))
+			p.APIBaseURL = srv.URL
+			p.SkipNameFallback = true
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Name != s.expected {
+				t.Fatalf("Expected name %q, got %q", s.expected, user.Name)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserPrivateName(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		person           string
+		skipNameFallback bool
+		expected         string
+	}{
+		{"null name", `{"name":null}`, false, "0000-0002-1825-0097"},
+		{"empty name fields", `{"name":{"given-names":{"value":""},"family-name":null,"credit-name":null}}`, false, "0000-0002-1825-0097"},
+		{"null name with disabled fallback", `{"name":null}`, true, ""},
+		{"public name", `{"name":{"given-names":{"value":"Josiah"}}}`, false, "Josiah"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, s.person)
+			}))
+			defer srv.Close()
+
+			p := NewORCIDProvider()
+			p.APIBaseURL = srv.URL
+			p.SkipNameFallback = s.skipNameFallback
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Name != s.expected {
+				t.Fatalf("Expected name %q, got %q", s.expected, user.Name)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserNameComponents(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected map[string]string
+	}{
+		{
+			"all components",
+			testORCIDPersonJSON,
+			map[string]string{"given_names": "Josiah", "family_name": "Carberry", "credit_name": "Josiah S. Carberry"},
+		},
+		{
+			"missing components",
+			`{"name":{"given-names":{"value":" Josiah "},"family-name":null}}`,
+			map[string]string{"given_names": "Josiah", "family_name": "", "credit_name": ""},
+		},
+		{
+			"private name",
+			`{"name":null}`,
+			map[string]string{"given_names": "", "family_name": "", "credit_name": ""},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			for key, expected := range s.expected {
+				v, ok := user.RawUser[key].(string)
+				if !ok {
+					t.Fatalf("Expected %s string key, got %T", key, user.RawUser[key])
+				}
+
+				if v != expected {
+					t.Fatalf("Expected %s %q, got %q", key, expected, v)
+				}
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserBiography(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected string
+	}{
+		{
+			"public biography",
+			testORCIDPersonJSON,
+			"Josiah Carberry is a fictitious person. This account is used as a demonstration account by ORCID, CrossRef and others.",
+		},
+		{
+			"null biography",
+			`{"name":{"given-names":{"value":"Josiah"}},"biography":null}`,
+			"",
+		},
+		{
+			"missing biography",
+			`{"name":{"given-names":{"value":"Josiah"}}}`,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			if v := user.RawUser["biography"]; v != s.expected {
+				t.Fatalf("Expected biography %q, got %#v", s.expected, v)
+			}
+		})
+	}
+}
+
+// testORCIDFetchAuthUser calls FetchAuthUser against a test server
+// that responds with the provided person JSON.
+func testORCIDFetchAuthUser(t *testing.T, person string) *AuthUser {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, person)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	return user
+}
+
+func TestORCIDRawUserSchemaVersion(t *testing.T) {
+	if ORCIDRawUserSchemaVersion != 1 {
+		t.Fatalf("Expected schema version 1 (update this test and the RawUser docs on change), got %d", ORCIDRawUserSchemaVersion)
+	}
+
+	user := testORCIDFetchAuthUser(t, testORCIDPersonJSON)
+
+	if v, ok := user.RawUser["raw_schema_version"].(int); !ok || v != ORCIDRawUserSchemaVersion {
+		t.Fatalf("Expected raw_schema_version %d, got %#v", ORCIDRawUserSchemaVersion, user.RawUser["raw_schema_version"])
+	}
+
+	// the documented raw /person keys replaced with normalized values
+	if _, ok := user.RawUser["biography"].(string); !ok {
+		t.Fatalf("Expected biography to be string, got %T", user.RawUser["biography"])
+	}
+	if _, ok := user.RawUser["emails"].([]ORCIDEmail); !ok {
+		t.Fatalf("Expected emails to be []ORCIDEmail, got %T", user.RawUser["emails"])
+	}
+	if _, ok := user.RawUser["keywords"].([]string); !ok {
+		t.Fatalf("Expected keywords to be []string, got %T", user.RawUser["keywords"])
+	}
+
+	// the other raw /person keys are kept as returned by the API
+	for _, key := range []string{"name", "addresses", "other-names", "researcher-urls", "external-identifiers"} {
+		if _, ok := user.RawUser[key].(map[string]any); !ok {
+			t.Fatalf("Expected raw %q to be kept as JSON object, got %T", key, user.RawUser[key])
+		}
+	}
+
+	// null person
+	user = testORCIDFetchAuthUser(t, `null`)
+
+	if v := user.RawUser["raw_schema_version"]; v != ORCIDRawUserSchemaVersion {
+		t.Fatalf("Expected raw_schema_version %d for null person, got %#v", ORCIDRawUserSchemaVersion, v)
+	}
+}
+
+func TestORCIDFetchAuthUserCountry(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected any
+	}{
+		{
+			"single address",
+			testORCIDPersonJSON,
+			"US",
+		},
+		{
+			"multiple addresses without primary",
+			`{"addresses":{"address":[{"country":{"value":""}},{"country":{"value":"BG"}},{"country":{"value":"DE"}}]}}`,
+			"BG",
+		},
+		{
+			"multiple addresses with primary",
+			`{"addresses":{"address":[{"country":{"value":"BG"}},{"country":{"value":"DE"},"primary":true},{"country":{"value":"FR"}}]}}`,
+			"DE",
+		},
+		{
+			"empty addresses",
+			`{"addresses":{"address":[]}}`,
+			nil,
+		},
+		{
+			"null addresses",
+			`{"addresses":null}`,
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			if v := user.RawUser["country"]; v != s.expected {
+				t.Fatalf("Expected country %#v, got %#v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserKeywords(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected []string
+	}{
+		{
+			"multiple keywords",
+			testORCIDPersonJSON,
+			[]string{"psychoceramics", "ionian philology"},
+		},
+		{
+			"empty and duplicated keywords",
+			`{"keywords":{"keyword":[{"content":"b"},{"content":" "},{"content":"a"},{"content":"B "},{"content":"c"}]}}`,
+			[]string{"b", "a", "c"},
+		},
+		{
+			"null keywords",
+			`{"keywords":null}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			keywords, ok := user.RawUser["keywords"].([]string)
+			if !ok {
+				t.Fatalf("Expected keywords to be []string, got %T", user.RawUser["keywords"])
+			}
+
+			if !slices.Equal(keywords, s.expected) {
+				t.Fatalf("Expected keywords %v, got %v", s.expected, keywords)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserResearcherURLs(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected []ORCIDResearcherURL
+	}{
+		{
+			"multiple urls",
+			testORCIDPersonJSON,
+			[]ORCIDResearcherURL{
+				{Name: "Brown University Page", URL: "http://library.brown.edu/about/hay/carberry.php", Visibility: ORCIDVisibilityPublic},
+				{Name: "", URL: "https://en.wikipedia.org/wiki/Josiah_S._Carberry", Visibility: ORCIDVisibilityPublic},
+			},
+		},
+		{
+			"empty url",
+			`{"researcher-urls":{"researcher-url":[{"url-name":"a","url":{"value":""}},{"url-name":"b","url":null},{"url-name":"c","url":{"value":"https://example.com"}}]}}`,
+			[]ORCIDResearcherURL{{Name: "c", URL: "https://example.com"}},
+		},
+		{
+			"null urls",
+			`{"researcher-urls":null}`,
+			[]ORCIDResearcherURL{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			urls, ok := user.RawUser["researcher_urls"].([]ORCIDResearcherURL)
+			if !ok {
+				t.Fatalf("Expected researcher_urls to be []ORCIDResearcherURL, got %T", user.RawUser["researcher_urls"])
+			}
+
+			if !slices.Equal(urls, s.expected) {
+				t.Fatalf("Expected researcher_urls %v, got %v", s.expected, urls)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserOtherNames(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected []string
+	}{
+		{
+			"multiple other names",
+			testORCIDPersonJSON,
+			[]string{"Josiah Stinkney Carberry", "J. S. Carberry"},
+		},
+		{
+			"empty other names",
+			`{"other-names":{"other-name":[]}}`,
+			[]string{},
+		},
+		{
+			"missing other names",
+			`{}`,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			otherNames, ok := user.RawUser["other_names"].([]string)
+			if !ok {
+				t.Fatalf("Expected other_names to be []string, got %T", user.RawUser["other_names"])
+			}
+
+			if !slices.Equal(otherNames, s.expected) {
+				t.Fatalf("Expected other_names %v, got %v", s.expected, otherNames)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserExternalIdentifiers(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected []ORCIDExternalIdentifier
+	}{
+		{
+			"multiple external identifiers",
+			testORCIDPersonJSON,
+			[]ORCIDExternalIdentifier{
+				{Type: "Scopus Author ID", Value: "7007156898", URL: "http://www.scopus.com/inward/authorDetails.url?authorID=7007156898&partnerID=MN8TOARS", Visibility: ORCIDVisibilityPublic},
+				{Type: "ResearcherID", Value: "A-1234-2011", URL: "", Visibility: ORCIDVisibilityPublic},
+				{Type: "ISNI", Value: "0000 0001 2146 438X", URL: "http://isni.org/isni/000000012146438X", Visibility: ORCIDVisibilityPublic},
+			},
+		},
+		{
+			"null external identifiers",
+			`{"external-identifiers":null}`,
+			[]ORCIDExternalIdentifier{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			ids, ok := user.RawUser["external_identifiers"].([]ORCIDExternalIdentifier)
+			if !ok {
+				t.Fatalf("Expected external_identifiers to be []ORCIDExternalIdentifier, got %T", user.RawUser["external_identifiers"])
+			}
+
+			if !slices.Equal(ids, s.expected) {
+				t.Fatalf("Expected external_identifiers %v, got %v", s.expected, ids)
+			}
+		})
+	}
+}
+
+func TestSelectORCIDEmail(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		emails   []ORCIDEmail
+		strict   bool
+		expected string
+	}{
+		{"no emails", nil, false, ""},
+		{"no emails (strict)", nil, true, ""},
+		{
+			"single unverified email",
+			[]ORCIDEmail{{Email: "a@example.com"}},
+			false,
+			"a@example.com",
+		},
+		{
+			"single unverified email (strict)",
+			[]ORCIDEmail{{Email: "a@example.com", Primary: true}},
+			true,
+			"",
+		},
+		{
+			"primary verified email",
+			[]ORCIDEmail{
+				{Email: "a@example.com"},
+				{Email: "b@example.com", Verified: true},
+				{Email: "c@example.com", Primary: true},
+				{Email: "d@example.com", Primary: true, Verified: true},
+			},
+			false,
+			"d@example.com",
+		},
+		{
+			"primary verified email (strict)",
+			[]ORCIDEmail{
+				{Email: "a@example.com"},
+				{Email: "b@example.com", Verified: true},
+				{Email: "c@example.com", Primary: true},
+				{Email: "d@example.com", Primary: true, Verified: true},
+			},
+			true,
+			"d@example.com",
+		},
+		{
+			"verified email",
+			[]ORCIDEmail{
+				{Email: "a@example.com"},
+				{Email: "b@example.com", Primary: true},
+				{Email: "c@example.com", Verified: true},
+				{Email: "d@example.com", Verified: true},
+			},
+			false,
+			"c@example.com",
+		},
+		{
+			"verified email (strict)",
+			[]ORCIDEmail{
+				{Email: "a@example.com"},
+				{Email: "b@example.com", Primary: true},
+				{Email: "c@example.com", Verified: true},
+				{Email: "d@example.com", Verified: true},
+			},
+			true,
+			"c@example.com",
+		},
+		{
+			"first non-empty email",
+			[]ORCIDEmail{
+				{Email: "", Primary: true, Verified: true},
+				{Email: "b@example.com"},
+				{Email: "c@example.com", Primary: true},
+			},
+			false,
+			"b@example.com",
+		},
+		{
+			"first non-empty email (strict)",
+			[]ORCIDEmail{
+				{Email: "", Primary: true, Verified: true},
+				{Email: "b@example.com"},
+				{Email: "c@example.com", Primary: true},
+			},
+			true,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := selectORCIDEmail(s.emails, s.strict)
+			if result != s.expected {
+				t.Fatalf("Expected email %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserEmail(t *testing.T) {
+	user := testORCIDFetchAuthUser(t, testORCIDPersonJSON)
+
+	expected := "josiah@example.com"
+	if user.Email != expected {
+		t.Fatalf("Expected email %q, got %q", expected, user.Email)
+	}
+}
+
+func TestORCIDFetchAuthUserEmails(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected []ORCIDEmail
+	}{
+		{
+			"multiple emails",
+			testORCIDPersonJSON,
+			[]ORCIDEmail{
+				{Email: "josiah.old@example.com", Visibility: ORCIDVisibilityPublic, Verified: true},
+				{Email: "josiah@example.com", Visibility: ORCIDVisibilityPublic, Primary: true, Verified: true},
+			},
+		},
+		{
+			"no emails",
+			`{"emails":{"email":[]}}`,
+			[]ORCIDEmail{},
+		},
+		{
+			"null emails",
+			`{"emails":null}`,
+			[]ORCIDEmail{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := testORCIDFetchAuthUser(t, s.person)
+
+			emails, ok := user.RawUser["emails"].([]ORCIDEmail)
+			if !ok {
+				t.Fatalf("Expected emails to be []ORCIDEmail, got %T", user.RawUser["emails"])
+			}
+
+			if !slices.Equal(emails, s.expected) {
+				t.Fatalf("Expected emails %v, got %v", s.expected, emails)
+			}
+
+			// should be serialized as an empty array and not as null
+			raw, err := json.Marshal(user.RawUser["emails"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s.expected) == 0 && string(raw) != "[]" {
+				t.Fatalf("Expected empty emails array, got %s", raw)
+			}
+		})
+	}
+}
+
+func TestORCIDEmailMatchesRecord(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		status        int
+		body          string
+		email         string
+		expected      bool
+		expectedError error
+	}{
+		{
+			"matching verified email",
+			http.StatusOK,
+			testORCIDEmailJSON,
+			"Josiah.Limited@Example.com",
+			true,
+			nil,
+		},
+		{
+			"non-matching email",
+			http.StatusOK,
+			testORCIDEmailJSON,
+			"other@example.com",
+			false,
+			nil,
+		},
+		{
+			"matching unverified email",
+			http.StatusOK,
+			`{"email": [{"email": "josiah@example.com", "verified": false, "primary": true}]}`,
+			"josiah@example.com",
+			false,
+			nil,
+		},
+		{
+			"private emails",
+			http.StatusOK,
+			`{"last-modified-date": null, "email": [], "path": "/0000-0002-1825-0097/email"}`,
+			"josiah@example.com",
+			false,
+			ErrORCIDEmailsUnavailable,
+		},
+		{
+			"forbidden emails",
+			http.StatusForbidden,
+			`{"response-code": 403, "developer-message": "insufficient scope", "error-code": 9017}`,
+			"josiah@example.com",
+			false,
+			ErrORCIDEmailsUnavailable,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			srv.setResponse("/v3.0/"+testORCIDServeriD+"/email", s.status, s.body)
+
+			matches, err := srv.provider().EmailMatchesRecord(srv.token(), s.email)
+
+			if s.expectedError == nil && err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if !errors.Is(err, s.expectedError) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, err)
+			}
+
+			if matches != s.expected {
+				t.Fatalf("Expected matches %v, got %v", s.expected, matches)
+			}
+		})
+	}
+
+	t.Run("missing email", func(t *testing.T) {
+		srv := newTestORCIDServer(t)
+
+		if _, err := srv.provider().EmailMatchesRecord(srv.token(), " "); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		if paths := srv.requestedPaths(); len(paths) != 0 {
+			t.Fatalf("Expected no requests, got %v", paths)
+		}
+	})
+}
+
+func TestORCIDFetchAuthUserExternalIdentifiersSource(t *testing.T) {
+	user := testORCIDFetchAuthUser(t, `{
+		"external-identifiers": {
+			"external-identifier": [
+				{
+					"source": {
+						"source-orcid": null,
+						"source-client-id": {"uri": "https://orcid.org/client/0000-0002-5982-8983", "path": "0000-0002-5982-8983", "host": "orcid.org"},
+						"source-name": {"value": "Scopus - Elsevier"}
+					},
+					"external-id-type": "Scopus Author ID",
+					"external-id-value": "7007156898"
+				},
+				{
+					"source": {
+						"source-orcid": {"uri": "https://orcid.org/0000-0002-1825-0097", "path": "0000-0002-1825-0097", "host": "orcid.org"},
+						"source-client-id": null,
+						"source-name": {"value": "Josiah Carberry"}
+					},
+					"external-id-type": "ResearcherID",
+					"external-id-value": "A-1234-2011"
+				},
+				{
+					"source": null,
+					"external-id-type": "ISNI",
+					"external-id-value": "0000 0001 2146 438X"
+				}
+			]
+		}
+	}`)
+
+	ids, ok := user.RawUser["external_identifiers"].([]ORCIDExternalIdentifier)
+	if !ok || len(ids) != 3 {
+		t.Fatalf("Expected 3 external_identifiers, got %#v", user.RawUser["external_identifiers"])
+	}
+
+	expected := []*ORCIDSource{
+		{Name: "Scopus - Elsevier", ClientId: "0000-0002-5982-8983"},
+		{Name: "Josiah Carberry", ORCID: "0000-0002-1825-0097"},
+		nil,
+	}
+
+	for i, id := range ids {
+		if !reflect.DeepEqual(id.Source, expected[i]) {
+			t.Fatalf("[%d] Expected source %#v, got %#v", i, expected[i], id.Source)
+		}
+	}
+
+	if !ids[0].Source.IsClient() || ids[1].Source.IsClient() || ids[2].Source.IsClient() {
+		t.Fatal("Expected only the first external identifier to be client asserted")
+	}
+}
+
+func TestORCIDFetchAuthUserVisibility(t *testing.T) {
+	user := testORCIDFetchAuthUser(t, `{
+		"name": {
+			"given-names": {"value": "Josiah"},
+			"family-name": {"value": "Carberry"},
+			"visibility": "limited"
+		},
+		"emails": {
+			"email": [
+				{"email": "josiah@example.com", "verified": true, "primary": true, "visibility": "public"},
+				{"email": "josiah.limited@example.com", "verified": true, "visibility": "LIMITED"},
+				{"email": "josiah.nofield@example.com", "verified": true}
+			]
+		},
+		"researcher-urls": {
+			"researcher-url": [
+				{"url-name": "lab", "url": {"value": "https://example.com/lab"}, "visibility": "limited"}
+			]
+		},
+		"external-identifiers": {
+			"external-identifier": [
+				{"external-id-type": "ResearcherID", "external-id-value": "A-1234-2011", "visibility": "public"}
+			]
+		}
+	}`)
+
+	if v := user.RawUser["name_visibility"]; v != ORCIDVisibilityLimited {
+		t.Fatalf("Expected name_visibility %q, got %v", ORCIDVisibilityLimited, v)
+	}
+
+	emails, _ := user.RawUser["emails"].([]ORCIDEmail)
+	expectedEmails := []string{ORCIDVisibilityPublic, ORCIDVisibilityLimited, ""}
+	if len(emails) != len(expectedEmails) {
+		t.Fatalf("Expected %d emails, got %v", len(expectedEmails), emails)
+	}
+	for i, email := range emails {
+		if email.Visibility != expectedEmails[i] {
+			t.Fatalf("[%d] Expected email visibility %q, got %q", i, expectedEmails[i], email.Visibility)
+		}
+	}
+
+	urls, _ := user.RawUser["researcher_urls"].([]ORCIDResearcherURL)
+	if len(urls) != 1 || urls[0].Visibility != ORCIDVisibilityLimited {
+		t.Fatalf("Expected a single limited researcher url, got %v", urls)
+	}
+
+	ids, _ := user.RawUser["external_identifiers"].([]ORCIDExternalIdentifier)
+	if len(ids) != 1 || ids[0].Visibility != ORCIDVisibilityPublic {
+		t.Fatalf("Expected a single public external identifier, got %v", ids)
+	}
+}
+
+func TestORCIDFetchEmail(t *testing.T) {
+	emailsJSON := `{
+		"email": [
+			{"email": "josiah.unverified@example.com", "verified": false, "primary": true, "visibility": "public"},
+			{"email": "josiah.old@example.com", "verified": true, "primary": false, "visibility": "public"},
+			{"email": "josiah@example.com", "verified": true, "primary": true, "visibility": "limited"}
+		]
+	}`
+
+	scenarios := []struct {
+		name           string
+		body           string
+		strict         bool
+		expectedEmail  string
+		expectedEmails int
+	}{
+		{"primary verified email", testORCIDEmailJSON, false, "josiah.limited@example.com", 1},
+		{"primary verified over others", emailsJSON, false, "josiah@example.com", 3},
+		{"verified fallback", `{"email":[{"email":"a@example.com","verified":false,"primary":true},{"email":"b@example.com","verified":true}]}`, false, "b@example.com", 2},
+		{"unverified non-strict", `{"email":[{"email":"a@example.com","verified":false,"primary":true}]}`, false, "a@example.com", 1},
+		{"unverified strict", `{"email":[{"email":"a@example.com","verified":false,"primary":true}]}`, true, "", 1},
+		{"no emails", `{"email":[]}`, false, "", 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			srv.setResponse("/v3.0/"+testORCIDServeriD+"/email", http.StatusOK, s.body)
+
+			p := srv.provider()
+			p.StrictEmail = s.strict
+
+			email, emails, err := p.FetchEmail(srv.token())
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, email)
+			}
+
+			if emails == nil || len(emails) != s.expectedEmails {
+				t.Fatalf("Expected %d emails, got %v", s.expectedEmails, emails)
+			}
+
+			expectedPaths := []string{"/v3.0/" + testORCIDServeriD + "/email"}
+			if paths := srv.requestedPaths(); !slices.Equal(paths, expectedPaths) {
+				t.Fatalf("Expected requested paths %v, got %v", expectedPaths, paths)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchPersonalDetails(t *testing.T) {
+	srv := newTestORCIDServer(t)
+	srv.setResponse("/v3.0/"+testORCIDServeriD+"/personal-details", http.StatusOK, `{
+		"last-modified-date": {"value": 1460757617080},
+		"name": {
+			"given-names": {"value": " Josiah "},
+			"family-name": {"value": "Carberry"},
+			"credit-name": {"value": "Josiah S. Carberry"},
+			"visibility": "public",
+			"path": "0000-0002-1825-0097"
+		},
+		"other-names": {
+			"other-name": [
+				{"content": "J. S. Carberry", "visibility": "public"},
+				{"content": "j. s. carberry", "visibility": "public"},
+				{"content": "Josiah Stinkney Carberry", "visibility": "public"}
+			]
+		},
+		"biography": {
+			"content": "Josiah Carberry is a fictitious person.",
+			"visibility": "public"
+		},
+		"path": "/0000-0002-1825-0097/personal-details"
+	}`)
+
+	scenarios := []struct {
+		strategy     ORCIDNameStrategy
+		expectedName string
+	}{
+		{ORCIDNameCreditThenGiven, "Josiah S. Carberry"},
+		{ORCIDNameGivenFamily, "Josiah Carberry"},
+		{ORCIDNameFamilyGiven, "Carberry, Josiah"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.expectedName, func(t *testing.T) {
+			p := srv.provider(WithORCIDNameStrategy(s.strategy))
+
+			details, err := p.FetchPersonalDetails(srv.token())
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			expected := &ORCIDPersonalDetails{
+				Name:           s.expectedName,
+				GivenNames:     "Josiah",
+				FamilyName:     "Carberry",
+				CreditName:     "Josiah S. Carberry",
+				NameVisibility: ORCIDVisibilityPublic,
+				Biography:      "Josiah Carberry is a fictitious person.",
+				OtherNames:     []string{"J. S. Carberry", "Josiah Stinkney Carberry"},
+			}
+
+			if !reflect.DeepEqual(details, expected) {
+				t.Fatalf("Expected details\n%#v\ngot\n%#v", expected, details)
+			}
+		})
+	}
+
+	t.Run("private details", func(t *testing.T) {
+		srv.setResponse("/v3.0/"+testORCIDServeriD+"/personal-details", http.StatusOK, `{"name": null, "other-names": null, "biography": null}`)
+
+		details, err := srv.provider().FetchPersonalDetails(srv.token())
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+
+		if details.Name != "" || details.Biography != "" || details.OtherNames == nil || len(details.OtherNames) != 0 {
+			t.Fatalf("Expected empty details, got %#v", details)
+		}
+	})
+}
+
+func TestORCIDFetchPerson(t *testing.T) {
+	srv := newTestORCIDServer(t)
+
+	p := srv.provider(WithORCIDRecord())
+
+	person, err := p.FetchPerson(srv.token())
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	user, err := p.FetchAuthUser(srv.token())
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	// the typed fields must match the RawUser values
+	checks := []struct {
+		key   string
+		value any
+	}{
+		{"orcid_uri", person.URI},
+		{"given_names", person.GivenNames},
+		{"family_name", person.FamilyName},
+		{"credit_name", person.CreditName},
+		{"name_visibility", person.NameVisibility},
+		{"other_names", person.OtherNames},
+		{"biography", person.Biography},
+		{"emails", person.Emails},
+		{"keywords", person.Keywords},
+		{"researcher_urls", person.ResearcherURLs},
+		{"country", person.Country},
+		{"external_identifiers", person.ExternalIdentifiers},
+		{"locale", person.Locale},
+		{"last_modified", person.LastModified},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(user.RawUser[c.key], c.value) {
+			t.Fatalf("Expected %s %#v, got %#v", c.key, user.RawUser[c.key], c.value)
+		}
+	}
+
+	if person.ORCID != testORCIDServeriD || person.Name != user.Name || person.Email != user.Email {
+		t.Fatalf("Expected iD, name and email to match the auth user, got %#v", person)
+	}
+
+	if person.Locale != "en" || person.Name == "" || person.LastModified.IsZero() || person.Created.IsZero() || len(person.Keywords) == 0 {
+		t.Fatalf("Expected a fully populated person, got %#v", person)
+	}
+
+	// json round-trip
+	raw, err := json.Marshal(person)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &ORCIDPerson{}
+	if err := json.Unmarshal(raw, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// the decoded time location pointer could differ (UTC vs nil)
+	if !decoded.LastModified.Time().Equal(person.LastModified.Time()) {
+		t.Fatalf("Expected round-tripped last_modified %v, got %v", person.LastModified, decoded.LastModified)
+	}
+	decoded.LastModified = person.LastModified
+
+	if !decoded.Created.Time().Equal(person.Created.Time()) {
+		t.Fatalf("Expected round-tripped created %v, got %v", person.Created, decoded.Created)
+	}
+	decoded.Created = person.Created
+
+	if !reflect.DeepEqual(decoded, person) {
+		t.Fatalf("Expected round-tripped person\n%#v\ngot\n%#v", person, decoded)
+	}
+}
+
+func TestORCIDPersonISNI(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		person   string
+		expected string
+	}{
+		{"record with ISNI", testORCIDPersonJSON, "000000012146438X"},
+		{
+			"record without ISNI",
+			`{"external-identifiers":{"external-identifier":[{"external-id-type":"ResearcherID","external-id-value":"A-1234-2011"}]}}`,
+			"",
+		},
+		{"record without external identifiers", `{"external-identifiers":null}`, ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			srv.setResponse("/v3.0/"+testORCIDServeriD+"/person", http.StatusOK, s.person)
+
+			person, err := srv.provider().FetchPerson(srv.token())
+			if err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if v := ORCIDPersonISNI(person); v != s.expected {
+				t.Fatalf("Expected ISNI %q, got %q", s.expected, v)
+			}
+		})
+	}
+
+	if v := ORCIDPersonISNI(nil); v != "" {
+		t.Fatalf("Expected empty ISNI for nil person, got %q", v)
+	}
+}
+
+func TestNormalizeORCIDISNI(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"000000012146438X", "000000012146438X"},
+		{"0000 0001 2146 438x", "000000012146438X"},
+		{" 0000-0001-2146-438X ", "000000012146438X"},
+		{"0000\u00a00001\u00a02146\u00a0438X", "000000012146438X"},
+		{"https://isni.org/isni/000000012146438X/", "000000012146438X"},
+		{"0000 0001 2146 4380", ""}, // invalid check character
+		{"0000 0001 2146 438", ""},
+		{"0000 0001 2146 438X 1", ""},
+		{"ABCD 0001 2146 438X", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := normalizeORCIDISNI(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
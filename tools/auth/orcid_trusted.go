@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+// ErrORCIDTrustedPartiesForbidden is returned by [ORCID.FetchTrustedParties]
+// when the token is not allowed to read the record trusted parties
+// (eg. missing "/read-limited" scope or a non-member client).
+var ErrORCIDTrustedPartiesForbidden = errors.New("the token is not allowed to read the ORCID trusted parties")
+
+// ORCIDTrustedParty defines a single client (aka. trusted organization)
+// that the user has granted access to their ORCID record.
+type ORCIDTrustedParty struct {
+	ClientId     string         `json:"client_id"`
+	Name         string         `json:"name"`
+	Website      string         `json:"website,omitempty"`
+	Scopes       []string       `json:"scopes"`
+	ApprovalDate types.DateTime `json:"approval_date"`
+}
+
+// FetchTrustedParties fetches the clients that the token user has
+// granted access to their ORCID record together with the granted scopes
+// (eg. for consent management dashboards).
+//
+// The trusted parties are available only in the member API and
+// require a token with the "/read-limited" scope, otherwise
+// [ErrORCIDTrustedPartiesForbidden] is returned.
+func (p *ORCID) FetchTrustedParties(token *oauth2.Token) ([]ORCIDTrustedParty, error) {
+	return p.FetchTrustedPartiesContext(p.ctx, token)
+}
+
+// FetchTrustedPartiesContext is similar to [ORCID.FetchTrustedParties] but uses the specified ctx for the requests.
+func (p *ORCID) FetchTrustedPartiesContext(ctx context.Context, token *oauth2.Token) ([]ORCIDTrustedParty, error) {
+	iD, err := orcidTokenId(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkORCIDTokenScope(token, ORCIDScopeReadLimited); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrORCIDTrustedPartiesForbidden, err)
+	}
+
+	url := p.apiURL(iD, "/trusted-parties")
+	if p.member && p.MemberAPIBaseURL != "" {
+		url = p.memberAPIURL(iD, "/trusted-parties")
+	}
+
+	data, err := p.fetchJSON(ctx, token, url)
+	if err != nil {
+		var apiErr *ORCIDAPIError
+		if errors.As(err, &apiErr) && (apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden) {
+			return nil, fmt.Errorf("%w: %w", ErrORCIDTrustedPartiesForbidden, err)
+		}
+		return nil, err
+	}
+
+	return parseORCIDTrustedParties(data)
+}
+
+// parseORCIDTrustedParties parses the raw /trusted-parties response.
+func parseORCIDTrustedParties(data []byte) ([]ORCIDTrustedParty, error) {
+	extracted := struct {
+		TrustedParty []struct {
+			Name         orcidJSONValue `json:"name"`
+			Website      orcidJSONValue `json:"website"`
+			Scopes       []string       `json:"scopes"`
+			ApprovalDate struct {
+				Value int64 `json:"value"` // epoch millis
+			} `json:"approval-date"`
+			Client struct {
+				Path string `json:"path"`
+			} `json:"client-id"`
+		} `json:"trusted-party"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	parties := make([]ORCIDTrustedParty, 0, len(extracted.TrustedParty))
+	for _, tp := range extracted.TrustedParty {
+		if tp.Client.Path == "" {
+			continue
+		}
+
+		party := ORCIDTrustedParty{
+			ClientId: tp.Client.Path,
+			Name:     tp.Name.Value,
+			Website:  tp.Website.Value,
+			Scopes:   tp.Scopes,
+		}
+		if party.Scopes == nil {
+			party.Scopes = []string{}
+		}
+		if tp.ApprovalDate.Value > 0 {
+			party.ApprovalDate, _ = types.ParseDateTime(time.UnixMilli(tp.ApprovalDate.Value))
+		}
+
+		parties = append(parties, party)
+	}
+
+	return parties, nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const testORCIDTrustedPartiesJSON = `{
+	"trusted-party": [
+		{
+			"client-id": {"uri": "https://orcid.org/client/APP-1111111111111111", "path": "APP-1111111111111111"},
+			"name": {"value": "Example University"},
+			"website": {"value": "https://example.edu"},
+			"approval-date": {"value": 1577836800000},
+			"scopes": ["/read-limited", "/activities/update"]
+		},
+		{
+			"client-id": {"path": "APP-2222222222222222"},
+			"name": {"value": "Example Publisher"},
+			"website": null,
+			"approval-date": null,
+			"scopes": null
+		},
+		{
+			"client-id": null,
+			"name": {"value": "Missing client id"}
+		}
+	]
+}`
+
+func TestParseORCIDTrustedParties(t *testing.T) {
+	parties, err := parseORCIDTrustedParties([]byte(testORCIDTrustedPartiesJSON))
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	approvalDate, _ := types.ParseDateTime(time.UnixMilli(1577836800000))
+
+	expected := []ORCIDTrustedParty{
+		{
+			ClientId:     "APP-1111111111111111",
+			Name:         "Example University",
+			Website:      "https://example.edu",
+			Scopes:       []string{"/read-limited", "/activities/update"},
+			ApprovalDate: approvalDate,
+		},
+		{
+			ClientId: "APP-2222222222222222",
+			Name:     "Example Publisher",
+			Scopes:   []string{},
+		},
+	}
+
+	if len(parties) != len(expected) {
+		t.Fatalf("Expected %d trusted parties, got %d", len(expected), len(parties))
+	}
+
+	for i, party := range parties {
+		if !party.ApprovalDate.Time().Equal(expected[i].ApprovalDate.Time()) {
+			t.Fatalf("[%d] Expected approval date %v, got %v", i, expected[i].ApprovalDate, party.ApprovalDate)
+		}
+		party.ApprovalDate = expected[i].ApprovalDate
+
+		if !reflect.DeepEqual(party, expected[i]) {
+			t.Fatalf("[%d] Expected\n%#v\ngot\n%#v", i, expected[i], party)
+		}
+	}
+}
+
+func TestORCIDFetchTrustedParties(t *testing.T) {
+	scenarios := []struct {
+		name            string
+		scope           string
+		status          int
+		expectedParties int
+		expectForbidden bool
+	}{
+		{"success", "/read-limited", http.StatusOK, 2, false},
+		{"missing read-limited scope", "/authenticate", http.StatusOK, 0, true},
+		{"forbidden response", "/read-limited", http.StatusForbidden, 0, true},
+		{"unauthorized response", "/read-limited", http.StatusUnauthorized, 0, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			srv := newTestORCIDServer(t)
+			srv.setResponse("/v3.0/"+testORCIDServeriD+"/trusted-parties", s.status, testORCIDTrustedPartiesJSON)
+
+			token := srv.token().WithExtra(map[string]any{
+				"orcid": testORCIDServeriD,
+				"scope": s.scope,
+			})
+
+			parties, err := srv.provider(WithORCIDMember()).FetchTrustedParties(token)
+
+			if errors.Is(err, ErrORCIDTrustedPartiesForbidden) != s.expectForbidden {
+				t.Fatalf("Expected forbidden error %v, got %v", s.expectForbidden, err)
+			}
+
+			if !s.expectForbidden && err != nil {
+				t.Fatalf("Expected nil error, got %v", err)
+			}
+
+			if len(parties) != s.expectedParties {
+				t.Fatalf("Expected %d trusted parties, got %d", s.expectedParties, len(parties))
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// isORCIDXMLContentType reports whether the specified response
+// Content-Type header value is an XML media type
+// (eg. "application/vnd.orcid+xml", "application/xml").
+func isORCIDXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/xml" ||
+		mediaType == "text/xml" ||
+		strings.HasSuffix(mediaType, "+xml")
+}
+
+// orcidXMLToJSON converts the provided ORCID XML document into its JSON representation.
+//
+// Only the person documents (eg. /person) are currently supported.
+func orcidXMLToJSON(data []byte) ([]byte, error) {
+	var person orcidXMLPerson
+	if err := xml.Unmarshal(data, &person); err != nil {
+		return nil, err
+	}
+
+	if person.XMLName.Local != "person" {
+		return nil, fmt.Errorf("unsupported ORCID XML response document %q", person.XMLName.Local)
+	}
+
+	return json.Marshal(person)
+}
+
+// orcidXMLValue defines a simple ORCID XML element which is
+// represented as {"value":"..."} object in the JSON response.
+type orcidXMLValue struct {
+	Value string `xml:",chardata" json:"value"`
+}
+
+// orcidXMLPerson defines the subset of the ORCID person XML document
+// which, when marshalized with encoding/json, has the same shape
+// as the ORCID person JSON response.
+//
+// The xml struct tags intentionally omit the namespaces since the
+// ORCID element local names are unique enough for our needs.
+type orcidXMLPerson struct {
+	XMLName xml.Name `json:"-"`
+
+	Name *struct {
+		Visibility string         `xml:"visibility,attr" json:"visibility"`
+		GivenNames *orcidXMLValue `xml:"given-names" json:"given-names"`
+		FamilyName *orcidXMLValue `xml:"family-name" json:"family-name"`
+		CreditName *orcidXMLValue `xml:"credit-name" json:"credit-name"`
+	} `xml:"name" json:"name"`
+
+	OtherNames struct {
+		OtherName []struct {
+			Content string `xml:"content" json:"content"`
+		} `xml:"other-name" json:"other-name"`
+	} `xml:"other-names" json:"other-names"`
+
+	Biography *struct {
+		Content string `xml:"content" json:"content"`
+	} `xml:"biography" json:"biography"`
+
+	ResearcherURLs struct {
+		ResearcherURL []struct {
+			URLName    string        `xml:"url-name" json:"url-name"`
+			URL        orcidXMLValue `xml:"url" json:"url"`
+			Visibility string        `xml:"visibility,attr" json:"visibility"`
+		} `xml:"researcher-url" json:"researcher-url"`
+	} `xml:"researcher-urls" json:"researcher-urls"`
+
+	Emails struct {
+		Email []struct {
+			Email      string `xml:"email" json:"email"`
+			Primary    bool   `xml:"primary,attr" json:"primary"`
+			Verified   bool   `xml:"verified,attr" json:"verified"`
+			Visibility string `xml:"visibility,attr" json:"visibility"`
+		} `xml:"email" json:"email"`
+	} `xml:"emails" json:"emails"`
+
+	Addresses struct {
+		Address []struct {
+			Country orcidXMLValue `xml:"country" json:"country"`
+		} `xml:"address" json:"address"`
+	} `xml:"addresses" json:"addresses"`
+
+	Keywords struct {
+		Keyword []struct {
+			Content string `xml:"content" json:"content"`
+		} `xml:"keyword" json:"keyword"`
+	} `xml:"keywords" json:"keywords"`
+
+	ExternalIdentifiers struct {
+		ExternalIdentifier []struct {
+			Type       string         `xml:"external-id-type" json:"external-id-type"`
+			Value      string         `xml:"external-id-value" json:"external-id-value"`
+			URL        *orcidXMLValue `xml:"external-id-url" json:"external-id-url"`
+			Visibility string         `xml:"visibility,attr" json:"visibility"`
+		} `xml:"external-identifier" json:"external-identifier"`
+	} `xml:"external-identifiers" json:"external-identifiers"`
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+const testORCIDPersonXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<person:person path="/0000-0002-1825-0097/person"
+	xmlns:person="http://www.orcid.org/ns/person"
+	xmlns:personal-details="http://www.orcid.org/ns/personal-details"
+	xmlns:other-name="http://www.orcid.org/ns/other-name"
+	xmlns:researcher-url="http://www.orcid.org/ns/researcher-url"
+	xmlns:email="http://www.orcid.org/ns/email"
+	xmlns:address="http://www.orcid.org/ns/address"
+	xmlns:keyword="http://www.orcid.org/ns/keyword"
+	xmlns:external-identifier="http://www.orcid.org/ns/external-identifier"
+	xmlns:common="http://www.orcid.org/ns/common">
+	<common:last-modified-date>2024-01-01T00:00:00.000Z</common:last-modified-date>
+	<person:name visibility="public" path="0000-0002-1825-0097">
+		<personal-details:given-names>Josiah</personal-details:given-names>
+		<personal-details:family-name>Carberry</personal-details:family-name>
+		<personal-details:credit-name>Josiah S. Carberry</personal-details:credit-name>
+	</person:name>
+	<other-name:other-names path="/0000-0002-1825-0097/other-names">
+		<other-name:other-name put-code="1" visibility="public">
+			<other-name:content>J. S. Carberry</other-name:content>
+		</other-name:other-name>
+	</other-name:other-names>
+	<person:biography visibility="public" path="/0000-0002-1825-0097/biography">
+		<personal-details:content>Josiah Carberry is a fictitious person.</personal-details:content>
+	</person:biography>
+	<researcher-url:researcher-urls path="/0000-0002-1825-0097/researcher-urls">
+		<researcher-url:researcher-url put-code="2" visibility="public">
+			<researcher-url:url-name>Wikipedia</researcher-url:url-name>
+			<researcher-url:url>https://en.wikipedia.org/wiki/Josiah_S._Carberry</researcher-url:url>
+		</researcher-url:researcher-url>
+	</researcher-url:researcher-urls>
+	<email:emails path="/0000-0002-1825-0097/email">
+		<email:email visibility="public" put-code="3" verified="true" primary="false">
+			<email:email>josiah2@example.com</email:email>
+		</email:email>
+		<email:email visibility="public" put-code="4" verified="true" primary="true">
+			<email:email>josiah@example.com</email:email>
+		</email:email>
+	</email:emails>
+	<address:addresses path="/0000-0002-1825-0097/address">
+		<address:address visibility="public" put-code="5">
+			<address:country>US</address:country>
+		</address:address>
+	</address:addresses>
+	<keyword:keywords path="/0000-0002-1825-0097/keywords">
+		<keyword:keyword visibility="public" put-code="6">
+			<keyword:content>psychoceramics</keyword:content>
+		</keyword:keyword>
+	</keyword:keywords>
+	<external-identifier:external-identifiers path="/0000-0002-1825-0097/external-identifiers">
+		<external-identifier:external-identifier visibility="public" put-code="7">
+			<common:external-id-type>Scopus Author ID</common:external-id-type>
+			<common:external-id-value>7007156898</common:external-id-value>
+			<common:external-id-url>http://www.scopus.com/inward/authorDetails.url?authorID=7007156898</common:external-id-url>
+		</external-identifier:external-identifier>
+	</external-identifier:external-identifiers>
+</person:person>`
+
+func TestIsORCIDXMLContentType(t *testing.T) {
+	scenarios := []struct {
+		contentType string
+		expected    bool
+	}{
+		{"", false},
+		{"invalid", false},
+		{"application/json", false},
+		{"application/json;charset=UTF-8", false},
+		{"application/vnd.orcid+json", false},
+		{"application/vnd.orcid+xml", true},
+		{"application/vnd.orcid+xml;charset=UTF-8", true},
+		{"application/xml", true},
+		{"text/xml; charset=utf-8", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.contentType, func(t *testing.T) {
+			if v := isORCIDXMLContentType(s.contentType); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestORCIDFetchAuthUserXML(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// simulate a gateway that has stripped the Accept header
+		w.Header().Set("Content-Type", "application/vnd.orcid+xml;charset=UTF-8")
+		fmt.Fprint(w, testORCIDPersonXML)
+	}))
+	defer srv.Close()
+
+	p := NewORCIDProvider()
+	p.APIBaseURL = srv.URL
+
+	token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{"orcid": "0000-0002-1825-0097"})
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Name != "Josiah S. Carberry" {
+		t.Fatalf("Expected name %q, got %q", "Josiah S. Carberry", user.Name)
+	}
+
+	if user.Email != "josiah@example.com" {
+		t.Fatalf("Expected email %q, got %q", "josiah@example.com", user.Email)
+	}
+
+	if v := user.RawUser["biography"]; v != "Josiah Carberry is a fictitious person." {
+		t.Fatalf("Expected biography, got %v", v)
+	}
+
+	if v := user.RawUser["country"]; v != "US" {
+		t.Fatalf("Expected country %q, got %v", "US", v)
+	}
+
+	if v, _ := user.RawUser["keywords"].([]string); !slices.Equal(v, []string{"psychoceramics"}) {
+		t.Fatalf("Expected keywords, got %v", user.RawUser["keywords"])
+	}
+
+	if v, _ := user.RawUser["other_names"].([]string); !slices.Equal(v, []string{"J. S. Carberry"}) {
+		t.Fatalf("Expected other names, got %v", user.RawUser["other_names"])
+	}
+
+	expectedURLs := []ORCIDResearcherURL{{Name: "Wikipedia", URL: "https://en.wikipedia.org/wiki/Josiah_S._Carberry", Visibility: ORCIDVisibilityPublic}}
+	if v, _ := user.RawUser["researcher_urls"].([]ORCIDResearcherURL); !slices.Equal(v, expectedURLs) {
+		t.Fatalf("Expected researcher urls %v, got %v", expectedURLs, user.RawUser["researcher_urls"])
+	}
+
+	expectedIds := []ORCIDExternalIdentifier{{
+		Type:       "Scopus Author ID",
+		Value:      "7007156898",
+		URL:        "http://www.scopus.com/inward/authorDetails.url?authorID=7007156898",
+		Visibility: ORCIDVisibilityPublic,
+	}}
+	if v, _ := user.RawUser["external_identifiers"].([]ORCIDExternalIdentifier); !slices.Equal(v, expectedIds) {
+		t.Fatalf("Expected external identifiers %v, got %v", expectedIds, user.RawUser["external_identifiers"])
+	}
+
+	if v, _ := user.RawUser["emails"].([]ORCIDEmail); len(v) != 2 {
+		t.Fatalf("Expected 2 emails, got %v", user.RawUser["emails"])
+	}
+}
+
+func TestORCIDXMLToJSONUnsupportedDocument(t *testing.T) {
+	_, err := orcidXMLToJSON([]byte(`<activities:works xmlns:activities="http://www.orcid.org/ns/activities"></activities:works>`))
+	if err == nil {
+		t.Fatal("Expected unsupported document error, got nil")
+	}
+}
@@ -178,4 +178,14 @@ export default [
         title: "ORCID",
         logo: "orcid.svg",
     },
+    {
+        key: "ORCIDSandbox",
+        title: "ORCID (sandbox)",
+        logo: "orcid.svg",
+    },
+    {
+        key: "ORCIDMember",
+        title: "ORCID (member)",
+        logo: "orcid.svg",
+    },
 ];